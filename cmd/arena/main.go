@@ -0,0 +1,181 @@
+// arena 讓兩個引擎對弈多局，報告勝和負、等級分差及信賴區間，並保存對局記錄
+//
+// 用法：
+//
+//	go run ./cmd/arena -a alphabeta,weights=weights.json -b alphabeta -games 200 -out games.json
+//
+// 引擎以「名稱,選項=值,...」指定：
+//
+//	random、greedy、alphabeta   內建難度 1-3 的引擎，選項 weights（權重文件）、threads、hash（MB）
+//	mcts                        蒙地卡羅樹搜索，選項 policy（capture、safe、random）、weights
+//	ext                         說 bpi 協議的外部引擎，選項 path（程序路徑）
+//
+// 對局記錄與 GET /api/games/player/:playerID 的格式相同，可直接作為 cmd/book 及 cmd/tune 的輸入。
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nelawu/BagchalGolang/internal/ai"
+	"github.com/nelawu/BagchalGolang/internal/ai/arena"
+	"github.com/nelawu/BagchalGolang/internal/ai/mcts"
+	"github.com/nelawu/BagchalGolang/internal/ai/protocol"
+	"github.com/nelawu/BagchalGolang/internal/domain/game"
+)
+
+func main() {
+	specA := flag.String("a", "alphabeta", "引擎 A")
+	specB := flag.String("b", "greedy", "引擎 B")
+	games := flag.Int("games", 20, "總局數，取偶數使每個開局雙方各執一次虎、羊")
+	randomPlies := flag.Int("random", 4, "開局隨機走的步數")
+	maxPlies := flag.Int("maxplies", 400, "每局最大步數，超過判和")
+	initial := flag.Int("tc", 15, "每方的初始時間（秒），引擎每步約用其 1/30")
+	increment := flag.Int("inc", 0, "每步加秒")
+	seed := flag.Int64("seed", time.Now().UnixNano(), "隨機開局的種子")
+	outPath := flag.String("out", "", "保存對局記錄的 JSON 文件")
+	flag.Parse()
+
+	a, closeA, err := newPlayer(*specA)
+	if err != nil {
+		log.Fatalf("創建引擎 A 失敗: %v", err)
+	}
+	defer closeA()
+	b, closeB, err := newPlayer(*specB)
+	if err != nil {
+		log.Fatalf("創建引擎 B 失敗: %v", err)
+	}
+	defer closeB()
+	if a.Name == b.Name {
+		a.Name, b.Name = a.Name+"#a", b.Name+"#b"
+	}
+
+	options := arena.Options{
+		Games:       *games,
+		RandomPlies: *randomPlies,
+		MaxPlies:    *maxPlies,
+		TimeControl: &game.TimeControl{InitialSeconds: *initial, IncrementSeconds: *increment},
+		Seed:        *seed,
+	}
+	log.Printf("%s 對 %s，共 %d 局，每步約 %v，種子 %d", a.Name, b.Name, *games,
+		ai.MoveBudget(&game.Game{TimeControl: options.TimeControl}), *seed)
+
+	report, err := arena.Play(a, b, options, func(i int, g *game.Game) {
+		result := "和"
+		switch g.State.Winner {
+		case game.Tiger:
+			result = g.TigerPlayerID + " 執虎勝"
+		case game.Goat:
+			result = g.GoatPlayerID + " 執羊勝"
+		}
+		log.Printf("第 %d 局：%s（%d 步）", i+1, result, len(g.History))
+	})
+	if err != nil {
+		log.Fatalf("對局失敗: %v", err)
+	}
+
+	for _, forfeit := range report.Forfeits {
+		log.Printf("第 %d 局：%s 出錯判負: %v", forfeit.Game+1, forfeit.Player, forfeit.Err)
+	}
+
+	diff, margin := report.Elo()
+	fmt.Printf("%s 對 %s：勝 %d、和 %d、負 %d（執虎勝 %d、執羊勝 %d），得分率 %.1f%%\n",
+		a.Name, b.Name, report.Wins, report.Draws, report.Losses, report.TigerWins, report.GoatWins, report.Score()*100)
+	fmt.Printf("等級分差 %+.0f ± %.0f（95%% 信賴區間）\n", diff, margin)
+
+	if *outPath != "" {
+		data, err := json.MarshalIndent(report.Games, "", "  ")
+		if err != nil {
+			log.Fatalf("序列化對局記錄失敗: %v", err)
+		}
+		if err := os.WriteFile(*outPath, data, 0o644); err != nil {
+			log.Fatalf("寫入對局記錄失敗: %v", err)
+		}
+		log.Printf("對局記錄已寫入 %s", *outPath)
+	}
+}
+
+// newPlayer 按「名稱,選項=值,...」創建引擎，返回的函數用於關閉外部引擎
+func newPlayer(spec string) (arena.Player, func(), error) {
+	name, rest, _ := strings.Cut(spec, ",")
+	options := make(map[string]string)
+	if rest != "" {
+		for _, pair := range strings.Split(rest, ",") {
+			key, value, found := strings.Cut(pair, "=")
+			if !found {
+				return arena.Player{}, nil, fmt.Errorf("無效的選項 %q", pair)
+			}
+			options[key] = value
+		}
+	}
+	player := arena.Player{Name: spec}
+	noop := func() {}
+
+	switch name {
+	case ai.EngineRandom, ai.EngineGreedy, ai.EngineAlphaBeta:
+		level := map[string]int{ai.EngineRandom: 1, ai.EngineGreedy: 2, ai.EngineAlphaBeta: 3}[name]
+		engine := ai.NewEngine(level)
+		hash := ai.DefaultTableSizeMB
+		if value, ok := options["hash"]; ok {
+			n, err := strconv.Atoi(value)
+			if err != nil {
+				return arena.Player{}, nil, err
+			}
+			hash = n
+		}
+		engine.SetTranspositionTable(ai.NewTranspositionTable(hash))
+		if value, ok := options["threads"]; ok {
+			n, err := strconv.Atoi(value)
+			if err != nil {
+				return arena.Player{}, nil, err
+			}
+			engine.SetThreads(n)
+		}
+		if path, ok := options["weights"]; ok {
+			weights, err := ai.LoadWeights(path)
+			if err != nil {
+				return arena.Player{}, nil, err
+			}
+			engine.SetWeights(weights)
+		}
+		player.Engine = engine
+	case "mcts":
+		config := mcts.DefaultConfig()
+		if value, ok := options["policy"]; ok {
+			policy, ok := mcts.Policies[value]
+			if !ok {
+				return arena.Player{}, nil, fmt.Errorf("未知的模擬策略 %q", value)
+			}
+			config.Policy = policy
+		}
+		if path, ok := options["weights"]; ok {
+			weights, err := ai.LoadWeights(path)
+			if err != nil {
+				return arena.Player{}, nil, err
+			}
+			config.Weights = weights
+		}
+		player.Engine = mcts.NewEngine(config)
+	case "ext":
+		path, ok := options["path"]
+		if !ok {
+			return arena.Player{}, nil, errors.New("外部引擎需指定 path")
+		}
+		client, err := protocol.Start(path)
+		if err != nil {
+			return arena.Player{}, nil, err
+		}
+		player.Engine = client
+		return player, func() { client.Close() }, nil
+	default:
+		return arena.Player{}, nil, fmt.Errorf("未知的引擎 %q", name)
+	}
+	return player, noop, nil
+}
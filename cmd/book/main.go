@@ -0,0 +1,80 @@
+// book 建立放置階段的開局庫
+//
+// 用法：
+//
+//	go run ./cmd/book -games games.json -selfplay 500 -out book.json
+//
+// games.json 為遊戲陣列（與 GET /api/games/player/:playerID 的返回格式相同），只收錄已結束的遊戲。
+// 自我對弈的引擎是確定性的，每局前 -random 步隨機走以得到不同的對局；-random 0 時所有自我對弈都是同一局。
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"log"
+	"math/rand"
+	"os"
+	"time"
+
+	"github.com/nelawu/BagchalGolang/internal/ai"
+	"github.com/nelawu/BagchalGolang/internal/ai/book"
+	"github.com/nelawu/BagchalGolang/internal/domain/game"
+)
+
+func main() {
+	gamesPath := flag.String("games", "", "已結束遊戲的 JSON 文件")
+	selfPlay := flag.Int("selfplay", 0, "自我對弈局數")
+	level := flag.Int("level", 3, "自我對弈的AI難度")
+	randomPlies := flag.Int("random", 4, "自我對弈開局隨機走的步數")
+	seed := flag.Int64("seed", 0, "隨機開局的種子，0 表示按時間生成")
+	maxPlies := flag.Int("maxplies", 200, "自我對弈每局的最大步數，超過判和")
+	basePath := flag.String("base", "", "在已有的開局庫上追加")
+	outPath := flag.String("out", "book.json", "輸出文件")
+	flag.Parse()
+
+	b := book.New()
+	if *basePath != "" {
+		var err error
+		if b, err = book.LoadFile(*basePath); err != nil {
+			log.Fatalf("讀取開局庫失敗: %v", err)
+		}
+	}
+
+	if *gamesPath != "" {
+		f, err := os.Open(*gamesPath)
+		if err != nil {
+			log.Fatalf("讀取遊戲文件失敗: %v", err)
+		}
+		var games []*game.Game
+		err = json.NewDecoder(f).Decode(&games)
+		f.Close()
+		if err != nil {
+			log.Fatalf("解析遊戲文件失敗: %v", err)
+		}
+		for _, g := range games {
+			b.AddGame(g)
+		}
+		log.Printf("已收錄 %d 局遊戲", len(games))
+	}
+
+	if *seed == 0 {
+		*seed = time.Now().UnixNano()
+	}
+	engine := ai.NewEngine(*level)
+	rng := rand.New(rand.NewSource(*seed))
+	for i := 0; i < *selfPlay; i++ {
+		g, err := book.SelfPlay(engine, *randomPlies, *maxPlies, rng)
+		if err != nil {
+			log.Fatalf("自我對弈失敗: %v", err)
+		}
+		b.AddGame(g)
+	}
+	if *selfPlay > 0 {
+		log.Printf("已完成 %d 局自我對弈", *selfPlay)
+	}
+
+	if err := b.SaveFile(*outPath); err != nil {
+		log.Fatalf("寫入開局庫失敗: %v", err)
+	}
+	log.Printf("開局庫共 %d 個局面，已寫入 %s", b.Size(), *outPath)
+}
@@ -0,0 +1,23 @@
+// engine 以 bpi 文字協議在標準輸入輸出上提供內建的 alpha-beta 搜索引擎
+//
+// 用法：
+//
+//	go build -o bagchal-engine ./cmd/engine
+//
+// 協議說明見 internal/ai/protocol 的包文檔。伺服器可通過 EXTERNAL_ENGINES 載入任何說此協議的引擎。
+package main
+
+import (
+	"log"
+	"os"
+
+	"github.com/nelawu/BagchalGolang/internal/ai"
+	"github.com/nelawu/BagchalGolang/internal/ai/protocol"
+)
+
+func main() {
+	server := protocol.NewServer("BagchalGolang alphabeta", ai.NewEngine(3))
+	if err := server.Serve(os.Stdin, os.Stdout); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -1,16 +1,36 @@
 package main
 
 import (
+	"fmt"
 	"github.com/gin-contrib/cors"
 	"log"
 	"os"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/nelawu/BagchalGolang/internal/ai"
+	"github.com/nelawu/BagchalGolang/internal/ai/book"
+	"github.com/nelawu/BagchalGolang/internal/ai/mcts"
+	"github.com/nelawu/BagchalGolang/internal/ai/protocol"
+	"github.com/nelawu/BagchalGolang/internal/ai/tablebase"
 	"github.com/nelawu/BagchalGolang/internal/api/handler"
+	"github.com/nelawu/BagchalGolang/internal/api/middleware"
+	"github.com/nelawu/BagchalGolang/internal/auth"
+	"github.com/nelawu/BagchalGolang/internal/domain/analysis"
+	"github.com/nelawu/BagchalGolang/internal/domain/chat"
 	"github.com/nelawu/BagchalGolang/internal/domain/game"
+	"github.com/nelawu/BagchalGolang/internal/domain/leaderboard"
+	"github.com/nelawu/BagchalGolang/internal/domain/matchmaking"
+	"github.com/nelawu/BagchalGolang/internal/domain/player"
+	"github.com/nelawu/BagchalGolang/internal/domain/puzzle"
+	"github.com/nelawu/BagchalGolang/internal/domain/rating"
+	"github.com/nelawu/BagchalGolang/internal/domain/rematch"
+	"github.com/nelawu/BagchalGolang/internal/domain/tournament"
 )
 
 // MemoryGameRepository 內存遊戲存儲實現
@@ -46,13 +66,25 @@ func (r *MemoryGameRepository) List(playerID string) ([]*game.Game, error) {
 	defer r.mu.RUnlock()
 	var playerGames []*game.Game
 	for _, g := range r.games {
-		if g.PlayerID == playerID {
+		if g.HasPlayer(playerID) {
 			playerGames = append(playerGames, g)
 		}
 	}
 	return playerGames, nil
 }
 
+func (r *MemoryGameRepository) ListFinished() ([]*game.Game, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var finishedGames []*game.Game
+	for _, g := range r.games {
+		if g.State.IsGameOver {
+			finishedGames = append(finishedGames, g)
+		}
+	}
+	return finishedGames, nil
+}
+
 func (r *MemoryGameRepository) Delete(id string) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
@@ -60,6 +92,437 @@ func (r *MemoryGameRepository) Delete(id string) error {
 	return nil
 }
 
+// MemoryPlayerRepository 內存玩家存儲實現
+type MemoryPlayerRepository struct {
+	players    map[string]*player.Player
+	byUsername map[string]*player.Player
+	mu         sync.RWMutex
+}
+
+func NewMemoryPlayerRepository() *MemoryPlayerRepository {
+	return &MemoryPlayerRepository{
+		players:    make(map[string]*player.Player),
+		byUsername: make(map[string]*player.Player),
+	}
+}
+
+func (r *MemoryPlayerRepository) Save(p *player.Player) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	// 在鎖內檢查用戶名，避免同時註冊相同用戶名時互相覆蓋
+	if existing, exists := r.byUsername[p.Username]; exists && existing.ID != p.ID {
+		return player.ErrUsernameTaken
+	}
+	r.players[p.ID] = p
+	r.byUsername[p.Username] = p
+	return nil
+}
+
+func (r *MemoryPlayerRepository) GetByID(id string) (*player.Player, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if p, exists := r.players[id]; exists {
+		return p, nil
+	}
+	return nil, player.ErrPlayerNotFound
+}
+
+func (r *MemoryPlayerRepository) GetByUsername(username string) (*player.Player, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if p, exists := r.byUsername[username]; exists {
+		return p, nil
+	}
+	return nil, player.ErrPlayerNotFound
+}
+
+// MemoryRatingRepository 內存積分存儲實現
+type MemoryRatingRepository struct {
+	ratings map[string]*rating.PlayerRating
+	mu      sync.RWMutex
+}
+
+func NewMemoryRatingRepository() *MemoryRatingRepository {
+	return &MemoryRatingRepository{
+		ratings: make(map[string]*rating.PlayerRating),
+	}
+}
+
+func (r *MemoryRatingRepository) Save(playerRating *rating.PlayerRating) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.ratings[playerRating.PlayerID] = playerRating
+	return nil
+}
+
+func (r *MemoryRatingRepository) GetByPlayerID(playerID string) (*rating.PlayerRating, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if playerRating, exists := r.ratings[playerID]; exists {
+		return playerRating, nil
+	}
+	return nil, rating.ErrRatingNotFound
+}
+
+func (r *MemoryRatingRepository) List() ([]*rating.PlayerRating, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	ratings := make([]*rating.PlayerRating, 0, len(r.ratings))
+	for _, playerRating := range r.ratings {
+		ratings = append(ratings, playerRating)
+	}
+	return ratings, nil
+}
+
+// MemoryTournamentRepository 內存賽事存儲實現
+type MemoryTournamentRepository struct {
+	tournaments map[string]*tournament.Tournament
+	mu          sync.RWMutex
+}
+
+func NewMemoryTournamentRepository() *MemoryTournamentRepository {
+	return &MemoryTournamentRepository{
+		tournaments: make(map[string]*tournament.Tournament),
+	}
+}
+
+func (r *MemoryTournamentRepository) Save(t *tournament.Tournament) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.tournaments[t.ID] = t
+	return nil
+}
+
+func (r *MemoryTournamentRepository) GetByID(id string) (*tournament.Tournament, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if t, exists := r.tournaments[id]; exists {
+		return t, nil
+	}
+	return nil, tournament.ErrTournamentNotFound
+}
+
+func (r *MemoryTournamentRepository) GetByGameID(gameID string) (*tournament.Tournament, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, t := range r.tournaments {
+		for _, round := range t.Rounds {
+			for _, pairing := range round.Pairings {
+				for _, g := range pairing.Games {
+					if g.GameID == gameID {
+						return t, nil
+					}
+				}
+			}
+		}
+	}
+	return nil, tournament.ErrTournamentNotFound
+}
+
+// MemoryChatRepository 內存聊天存儲實現
+type MemoryChatRepository struct {
+	messages map[string][]*chat.Message // 遊戲ID -> 按ID排序的訊息
+	mutes    map[string]map[string]bool // 玩家ID -> 屏蔽的玩家
+	nextID   uint64
+	mu       sync.RWMutex
+}
+
+func NewMemoryChatRepository() *MemoryChatRepository {
+	return &MemoryChatRepository{
+		messages: make(map[string][]*chat.Message),
+		mutes:    make(map[string]map[string]bool),
+	}
+}
+
+func (r *MemoryChatRepository) Save(message *chat.Message) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	// 在鎖內分配遞增的ID，使訊息列表按ID有序，ListByGame 可二分查找
+	r.nextID++
+	message.ID = fmt.Sprintf("msg_%016d", r.nextID)
+	r.messages[message.GameID] = append(r.messages[message.GameID], message)
+	return nil
+}
+
+func (r *MemoryChatRepository) ListByGame(gameID, afterID string, limit int) ([]*chat.Message, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	messages := r.messages[gameID]
+	if afterID != "" {
+		start := sort.Search(len(messages), func(i int) bool {
+			return messages[i].ID > afterID
+		})
+		messages = messages[start:]
+	} else if len(messages) > limit {
+		// 未指定起點時返回最近的訊息
+		messages = messages[len(messages)-limit:]
+	}
+	if len(messages) > limit {
+		messages = messages[:limit]
+	}
+	return append([]*chat.Message(nil), messages...), nil
+}
+
+func (r *MemoryChatRepository) SetMuted(playerID, mutedPlayerID string, muted bool) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !muted {
+		delete(r.mutes[playerID], mutedPlayerID)
+		return nil
+	}
+	if r.mutes[playerID] == nil {
+		r.mutes[playerID] = make(map[string]bool)
+	}
+	r.mutes[playerID][mutedPlayerID] = true
+	return nil
+}
+
+func (r *MemoryChatRepository) ListMuted(playerID string) ([]string, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	muted := make([]string, 0, len(r.mutes[playerID]))
+	for id := range r.mutes[playerID] {
+		muted = append(muted, id)
+	}
+	sort.Strings(muted)
+	return muted, nil
+}
+
+// MemorySeriesRepository 內存再戰系列存儲實現
+type MemorySeriesRepository struct {
+	series map[string]*rematch.Series
+	mu     sync.RWMutex
+}
+
+func NewMemorySeriesRepository() *MemorySeriesRepository {
+	return &MemorySeriesRepository{
+		series: make(map[string]*rematch.Series),
+	}
+}
+
+func (r *MemorySeriesRepository) Save(series *rematch.Series) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.series[series.ID] = series
+	return nil
+}
+
+func (r *MemorySeriesRepository) GetByID(id string) (*rematch.Series, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	series, exists := r.series[id]
+	if !exists {
+		return nil, rematch.ErrSeriesNotFound
+	}
+	return series, nil
+}
+
+// MemoryPuzzleRepository 內存謎題存儲實現
+type MemoryPuzzleRepository struct {
+	puzzles  map[string]*puzzle.Puzzle
+	byKey    map[string]*puzzle.Puzzle
+	attempts map[string]*puzzle.Attempt // 以「謎題ID/玩家ID」為鍵
+	mu       sync.RWMutex
+}
+
+func NewMemoryPuzzleRepository() *MemoryPuzzleRepository {
+	return &MemoryPuzzleRepository{
+		puzzles:  make(map[string]*puzzle.Puzzle),
+		byKey:    make(map[string]*puzzle.Puzzle),
+		attempts: make(map[string]*puzzle.Attempt),
+	}
+}
+
+func (r *MemoryPuzzleRepository) Save(p *puzzle.Puzzle) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.puzzles[p.ID] = p
+	r.byKey[p.Key] = p
+	return nil
+}
+
+func (r *MemoryPuzzleRepository) GetByID(id string) (*puzzle.Puzzle, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if p, exists := r.puzzles[id]; exists {
+		return p, nil
+	}
+	return nil, puzzle.ErrPuzzleNotFound
+}
+
+func (r *MemoryPuzzleRepository) GetByKey(key string) (*puzzle.Puzzle, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if p, exists := r.byKey[key]; exists {
+		return p, nil
+	}
+	return nil, puzzle.ErrPuzzleNotFound
+}
+
+func (r *MemoryPuzzleRepository) List(filter puzzle.Filter) ([]*puzzle.Puzzle, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	puzzles := []*puzzle.Puzzle{}
+	for _, p := range r.puzzles {
+		if filter.Theme != "" && !p.HasTheme(filter.Theme) {
+			continue
+		}
+		if (filter.MinRating > 0 && p.Rating < filter.MinRating) || (filter.MaxRating > 0 && p.Rating > filter.MaxRating) {
+			continue
+		}
+		puzzles = append(puzzles, p)
+	}
+	sort.Slice(puzzles, func(i, j int) bool { return puzzles[i].Rating < puzzles[j].Rating })
+	if filter.Limit > 0 && len(puzzles) > filter.Limit {
+		puzzles = puzzles[:filter.Limit]
+	}
+	return puzzles, nil
+}
+
+func (r *MemoryPuzzleRepository) SaveAttempt(attempt *puzzle.Attempt) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.attempts[attempt.PuzzleID+"/"+attempt.PlayerID] = attempt
+	return nil
+}
+
+func (r *MemoryPuzzleRepository) GetAttempt(puzzleID, playerID string) (*puzzle.Attempt, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if attempt, exists := r.attempts[puzzleID+"/"+playerID]; exists {
+		return attempt, nil
+	}
+	return nil, puzzle.ErrAttemptNotFound
+}
+
+// newEngineRegistry 按環境變數創建各難度的AI引擎，並返回供分析及謎題使用的搜索引擎，
+// 以及已啟動的外部引擎（伺服器結束時需關閉）
+// 開局庫、殘局庫、評估權重、置換表及線程數由所有內建引擎共用
+func newEngineRegistry() (*ai.Registry, *ai.Engine, []*protocol.Pool) {
+	// 置換表在所有遊戲及各步之間共用，大小可通過 TT_SIZE_MB 設置
+	tableSize := ai.DefaultTableSizeMB
+	if value := os.Getenv("TT_SIZE_MB"); value != "" {
+		size, err := strconv.Atoi(value)
+		if err != nil || size <= 0 {
+			log.Fatalf("無效的置換表大小: %s", value)
+		}
+		tableSize = size
+	}
+	table := ai.NewTranspositionTable(tableSize)
+
+	// 搜索預設使用所有 CPU 核心，可通過 AI_THREADS 設置，1 表示單線程
+	threads := runtime.NumCPU()
+	if value := os.Getenv("AI_THREADS"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n <= 0 {
+			log.Fatalf("無效的搜索線程數: %s", value)
+		}
+		threads = n
+	}
+
+	// 開局庫可由 cmd/book 生成，通過 OPENING_BOOK 指定文件
+	var openingBook *book.Book
+	if path := os.Getenv("OPENING_BOOK"); path != "" {
+		var err error
+		if openingBook, err = book.LoadFile(path); err != nil {
+			log.Fatalf("讀取開局庫失敗: %v", err)
+		}
+		log.Printf("已載入開局庫，共 %d 個局面", openingBook.Size())
+	}
+
+	// 評估權重可由 EVAL_WEIGHTS 指定 JSON 文件，未出現的特徵使用預設權重
+	weights := ai.DefaultWeights()
+	if path := os.Getenv("EVAL_WEIGHTS"); path != "" {
+		var err error
+		if weights, err = ai.LoadWeights(path); err != nil {
+			log.Fatalf("讀取評估權重失敗: %v", err)
+		}
+		log.Printf("已載入評估權重: %+v", weights)
+	}
+
+	// 殘局庫可由 cmd/tablebase 生成，通過 TABLEBASE_DIR 指定目錄
+	var tb *tablebase.Tablebase
+	if dir := os.Getenv("TABLEBASE_DIR"); dir != "" {
+		var err error
+		if tb, err = tablebase.Load(dir); err != nil {
+			log.Fatalf("讀取殘局庫失敗: %v", err)
+		}
+		log.Printf("已載入殘局庫，共 %d 張表", len(tb.Tables()))
+	}
+
+	registry := ai.NewRegistry(2) // 未知難度按中等難度處理
+	var searchEngine *ai.Engine
+	for level := 1; level <= 3; level++ {
+		engine := ai.NewEngine(level)
+		engine.SetTranspositionTable(table)
+		engine.SetThreads(threads)
+		engine.SetOpeningBook(openingBook)
+		engine.SetWeights(weights)
+		engine.SetTablebase(tb)
+		registry.Register(ai.LevelEngines[level], engine)
+		registry.Assign(level, ai.LevelEngines[level])
+		if level == 3 {
+			searchEngine = engine
+		}
+	}
+
+	// 蒙地卡羅樹搜索引擎，MCTS_POLICY 選擇模擬策略
+	config := mcts.DefaultConfig()
+	config.Weights = weights
+	if name := os.Getenv("MCTS_POLICY"); name != "" {
+		policy, ok := mcts.Policies[name]
+		if !ok {
+			log.Fatalf("未知的模擬策略: %s", name)
+		}
+		config.Policy = policy
+	}
+	registry.Register("mcts", mcts.NewEngine(config))
+
+	// EXTERNAL_ENGINES 載入說 bpi 協議的外部引擎，例如 "mine=/usr/local/bin/my-engine"
+	// 每個引擎啟動 EXTERNAL_ENGINE_PROCESSES 個程序（預設 2），讓多局遊戲可以同時思考
+	var externals []*protocol.Pool
+	processes := 2
+	if value := os.Getenv("EXTERNAL_ENGINE_PROCESSES"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 {
+			log.Fatalf("無效的外部引擎程序數: %s", value)
+		}
+		processes = n
+	}
+	if value := os.Getenv("EXTERNAL_ENGINES"); value != "" {
+		for _, pair := range strings.Split(value, ",") {
+			name, path, found := strings.Cut(strings.TrimSpace(pair), "=")
+			if !found || name == "" || path == "" {
+				log.Fatalf("無效的外部引擎設置: %s", pair)
+			}
+			pool, err := protocol.NewPool(processes, path)
+			if err != nil {
+				log.Fatalf("啟動外部引擎 %s 失敗: %v", name, err)
+			}
+			registry.Register(name, pool)
+			externals = append(externals, pool)
+			log.Printf("已啟動外部引擎 %s: %s（%d 個程序）", name, pool.Name, processes)
+		}
+	}
+
+	// AI_LEVELS 可改變難度對應的引擎，例如 "3=mcts"
+	if value := os.Getenv("AI_LEVELS"); value != "" {
+		for _, pair := range strings.Split(value, ",") {
+			levelText, name, found := strings.Cut(strings.TrimSpace(pair), "=")
+			level, err := strconv.Atoi(levelText)
+			if !found || err != nil {
+				log.Fatalf("無效的難度設置: %s", pair)
+			}
+			if err := registry.Assign(level, name); err != nil {
+				log.Fatalf("難度 %d 指定了未知的引擎: %s（可用：%s）", level, name, strings.Join(registry.Names(), ", "))
+			}
+		}
+	}
+	log.Printf("AI難度對應的引擎: %v", registry.Levels())
+
+	return registry, searchEngine, externals
+}
+
 func main() {
 	log.Println("創建路由")
 	// 創建路由
@@ -69,7 +532,7 @@ func main() {
 	router.Use(cors.New(cors.Config{
 		AllowOrigins:     []string{"*"}, // 允許的來源
 		AllowMethods:     []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Accept"},
+		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization"},
 		ExposeHeaders:    []string{"Content-Length"},
 		AllowCredentials: false,
 		MaxAge:           12 * time.Hour,
@@ -77,13 +540,97 @@ func main() {
 
 	log.Println("初始化依賴")
 	// 初始化依賴
+	// 會話令牌簽名密鑰，未設置時使用隨機密鑰（重啟後需重新登入）
+	secret := []byte(os.Getenv("AUTH_SECRET"))
+	if len(secret) == 0 {
+		log.Println("未設置 AUTH_SECRET，使用隨機簽名密鑰")
+		var err error
+		secret, err = auth.NewRandomSecret()
+		if err != nil {
+			log.Fatalf("生成簽名密鑰失敗: %v", err)
+		}
+	}
+	tokenIssuer := auth.NewTokenIssuer(secret, 24*time.Hour)
+	requireAuth := middleware.RequireAuth(tokenIssuer)
+
+	playerRepo := NewMemoryPlayerRepository()
+	playerService := player.NewPlayerService(playerRepo)
+	playerHandler := handler.NewPlayerHandler(playerService)
+	authHandler := handler.NewAuthHandler(playerService, tokenIssuer)
+
 	gameRepo := NewMemoryGameRepository()
-	aiEngine := ai.NewEngine(2) // 默認中等難度
-	gameService := game.NewGameService(gameRepo, aiEngine)
+	engines, analysisEngine, externalEngines := newEngineRegistry()
+	for _, pool := range externalEngines {
+		defer pool.Close()
+	}
+	gameService := game.NewGameService(gameRepo, engines, playerService)
 	gameHandler := handler.NewGameHandler(gameService)
 
+	// 遊戲結束後在背景生成賽後註解
+	analysisService := analysis.NewAnalysisService(analysisEngine, gameService)
+	analysisService.Start()
+	defer analysisService.Stop()
+	gameService.OnGameOver(analysisService.HandleGameOver)
+	analysisHandler := handler.NewAnalysisHandler(analysisService)
+
+	// 從已結束的遊戲及引擎自我對弈中挖掘謎題
+	puzzleRepo := NewMemoryPuzzleRepository()
+	puzzleService := puzzle.NewPuzzleService(puzzleRepo, analysisEngine, engines.EngineForLevel(2))
+	puzzleService.Start()
+	defer puzzleService.Stop()
+	gameService.OnGameOver(puzzleService.HandleGameOver)
+	puzzleHandler := handler.NewPuzzleHandler(puzzleService)
+
+	ratingRepo := NewMemoryRatingRepository()
+	ratingService := rating.NewService(ratingRepo, playerService)
+	gameService.OnGameOver(ratingService.HandleGameOver)
+	ratingHandler := handler.NewRatingHandler(ratingService)
+
+	leaderboardService := leaderboard.NewService(ratingService)
+	if err := leaderboardService.Rebuild(gameService); err != nil {
+		log.Fatalf("重建排行榜失敗: %v", err)
+	}
+	gameService.OnGameOver(leaderboardService.HandleGameOver)
+	leaderboardHandler := handler.NewLeaderboardHandler(leaderboardService)
+
+	matchmakingService := matchmaking.NewService(gameService, ratingService, playerService, matchmaking.DefaultConfig())
+	matchmakingService.Start()
+	defer matchmakingService.Stop()
+	matchmakingHandler := handler.NewMatchmakingHandler(matchmakingService)
+
+	// 聊天過濾器：屏蔽詞列表可通過 CHAT_BLOCKED_WORDS（逗號分隔）設置
+	chatRepo := NewMemoryChatRepository()
+	chatService := chat.NewChatService(chatRepo, gameService,
+		chat.NewRateLimitFilter(5, 10*time.Second),
+		chat.NewRepeatFilter(10*time.Minute),
+		chat.NewWordFilter(strings.Split(os.Getenv("CHAT_BLOCKED_WORDS"), ",")),
+	)
+	chatHandler := handler.NewChatHandler(chatService)
+
+	tournamentRepo := NewMemoryTournamentRepository()
+	tournamentService := tournament.NewTournamentService(tournamentRepo, gameService, playerService)
+	gameService.OnGameOver(tournamentService.HandleGameOver)
+	tournamentService.Start()
+	defer tournamentService.Stop()
+	tournamentHandler := handler.NewTournamentHandler(tournamentService)
+
+	seriesRepo := NewMemorySeriesRepository()
+	rematchService := rematch.NewRematchService(seriesRepo, gameService)
+	gameService.OnGameOver(rematchService.HandleGameOver)
+	rematchHandler := handler.NewRematchHandler(rematchService)
+
 	// 註冊路由
-	gameHandler.RegisterRoutes(router)
+	authHandler.RegisterRoutes(router)
+	playerHandler.RegisterRoutes(router, requireAuth)
+	gameHandler.RegisterRoutes(router, requireAuth)
+	matchmakingHandler.RegisterRoutes(router, requireAuth)
+	ratingHandler.RegisterRoutes(router)
+	leaderboardHandler.RegisterRoutes(router)
+	tournamentHandler.RegisterRoutes(router, requireAuth)
+	chatHandler.RegisterRoutes(router, requireAuth)
+	rematchHandler.RegisterRoutes(router, requireAuth)
+	analysisHandler.RegisterRoutes(router, requireAuth)
+	puzzleHandler.RegisterRoutes(router, requireAuth)
 
 	// 添加健康檢查端點
 	router.GET("/health", func(c *gin.Context) {
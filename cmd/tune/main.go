@@ -0,0 +1,93 @@
+// tune 從對局中自動調整靜態評估的權重
+//
+// 用法：
+//
+//	go run ./cmd/tune -games games.json -selfplay 500 -out weights.json
+//
+// games.json 為遊戲陣列（與 GET /api/games/player/:playerID 的返回格式相同），只使用已結束的遊戲。
+// 輸出的權重文件可通過 EVAL_WEIGHTS 載入。
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"log"
+	"math/rand"
+	"os"
+	"time"
+
+	"github.com/nelawu/BagchalGolang/internal/ai"
+	"github.com/nelawu/BagchalGolang/internal/ai/tune"
+	"github.com/nelawu/BagchalGolang/internal/domain/game"
+)
+
+func main() {
+	gamesPath := flag.String("games", "", "已結束遊戲的 JSON 文件")
+	selfPlay := flag.Int("selfplay", 0, "自我對弈局數")
+	depth := flag.Int("depth", 1, "自我對弈的搜索深度")
+	randomPlies := flag.Int("random", 8, "自我對弈開局隨機走的步數")
+	maxPlies := flag.Int("maxplies", 200, "自我對弈每局的最大步數，超過判和")
+	skipPlies := flag.Int("skip", 4, "每局跳過的開局步數")
+	basePath := flag.String("base", "", "初始權重文件，省略時使用預設權重")
+	outPath := flag.String("out", "weights.json", "輸出文件")
+	flag.Parse()
+
+	initial := ai.DefaultWeights()
+	if *basePath != "" {
+		var err error
+		if initial, err = ai.LoadWeights(*basePath); err != nil {
+			log.Fatalf("讀取權重失敗: %v", err)
+		}
+	}
+
+	var samples []tune.Sample
+	if *gamesPath != "" {
+		f, err := os.Open(*gamesPath)
+		if err != nil {
+			log.Fatalf("讀取遊戲文件失敗: %v", err)
+		}
+		var games []*game.Game
+		err = json.NewDecoder(f).Decode(&games)
+		f.Close()
+		if err != nil {
+			log.Fatalf("解析遊戲文件失敗: %v", err)
+		}
+		for _, g := range games {
+			samples = append(samples, tune.Samples(g, *skipPlies)...)
+		}
+		log.Printf("已收錄 %d 局遊戲", len(games))
+	}
+
+	// 以初始權重自我對弈，之後的調參即修正這些權重對實際結果的誤判
+	engine := ai.NewEngine(3)
+	engine.SetWeights(initial)
+	rng := rand.New(rand.NewSource(time.Now().UnixNano()))
+	outcomes := make(map[game.PieceType]int)
+	for i := 0; i < *selfPlay; i++ {
+		g, err := tune.SelfPlay(engine, ai.Limits{Depth: *depth}, *randomPlies, *maxPlies, rng)
+		if err != nil {
+			log.Fatalf("自我對弈失敗: %v", err)
+		}
+		outcomes[g.State.Winner]++
+		samples = append(samples, tune.Samples(g, *skipPlies)...)
+	}
+	if *selfPlay > 0 {
+		log.Printf("已完成 %d 局自我對弈：虎勝 %d、羊勝 %d、和 %d",
+			*selfPlay, outcomes[game.Tiger], outcomes[game.Goat], outcomes[game.Empty])
+	}
+	if len(samples) == 0 {
+		log.Fatal("沒有可用的局面，請指定 -games 或 -selfplay")
+	}
+
+	result := tune.Tune(samples, initial, tune.DefaultOptions())
+	log.Printf("共 %d 個局面，K=%.5f，誤差 %.5f -> %.5f（%d 輪）",
+		len(samples), result.K, result.InitialError, result.FinalError, result.Passes)
+	for i, name := range ai.FeatureNames {
+		log.Printf("  %-16s %5d -> %5d", name, initial.Values()[i], result.Weights.Values()[i])
+	}
+
+	if err := ai.SaveWeights(*outPath, result.Weights); err != nil {
+		log.Fatalf("寫入權重失敗: %v", err)
+	}
+	log.Printf("權重已寫入 %s", *outPath)
+}
@@ -0,0 +1,194 @@
+// Package arena 讓兩個引擎對弈多局並統計結果，用於驗證引擎的改動是否真的更強
+package arena
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"time"
+
+	"github.com/nelawu/BagchalGolang/internal/domain/game"
+)
+
+// Player 參賽的引擎
+type Player struct {
+	Name   string
+	Engine game.AIEngine
+}
+
+// Options 對局設置
+type Options struct {
+	Games       int               // 總局數，每個隨機開局雙方各執虎、羊一次
+	RandomPlies int               // 開局隨機走的步數
+	MaxPlies    int               // 每局最大步數，超過判和
+	TimeControl *game.TimeControl // 決定引擎每步的思考時間，見 ai.MoveBudget
+	Seed        int64             // 隨機開局及各局遊戲種子的來源
+}
+
+// Report 以第一個引擎（A）的視角統計的結果
+type Report struct {
+	Wins, Draws, Losses int
+	TigerWins           int // A 執虎時的勝局
+	GoatWins            int // A 執羊時的勝局
+	Games               []*game.Game
+	Forfeits            []Forfeit // 引擎出錯而判負的對局
+}
+
+// Forfeit 記錄一方引擎出錯或走出非法移動而判負的對局
+type Forfeit struct {
+	Game   int    // 對局序號，對應 Report.Games
+	Player string // 出錯的引擎
+	Err    error
+}
+
+// Play 進行對局，每局結束後調用 progress（可為空）
+// 第 2k 與 2k+1 局使用同一隨機開局並交換陣營，抵消開局及先後手的偏差
+// 引擎出錯時該局判出錯的一方負並記入 Report.Forfeits，對局繼續進行
+func Play(a, b Player, options Options, progress func(index int, g *game.Game)) (*Report, error) {
+	rng := rand.New(rand.NewSource(options.Seed))
+	report := &Report{}
+
+	var opening []game.Move
+	for i := 0; i < options.Games; i++ {
+		tiger, goat := a, b
+		if i%2 == 0 {
+			opening = randomOpening(rng, options.RandomPlies)
+		} else {
+			tiger, goat = b, a
+		}
+
+		g, forfeit, err := playGame(tiger, goat, opening, rng.Int63(), options)
+		if err != nil {
+			return nil, err
+		}
+		if forfeit != nil {
+			forfeit.Game = i
+			report.Forfeits = append(report.Forfeits, *forfeit)
+		}
+		report.Games = append(report.Games, g)
+
+		aSide := game.Tiger
+		if i%2 == 1 {
+			aSide = game.Goat
+		}
+		switch g.State.Winner {
+		case game.Empty:
+			report.Draws++
+		case aSide:
+			report.Wins++
+			if aSide == game.Tiger {
+				report.TigerWins++
+			} else {
+				report.GoatWins++
+			}
+		default:
+			report.Losses++
+		}
+		if progress != nil {
+			progress(i, g)
+		}
+	}
+	return report, nil
+}
+
+// randomOpening 隨機走 plies 步，返回移動記錄；中途分出勝負時縮短
+func randomOpening(rng *rand.Rand, plies int) []game.Move {
+	state := game.NewGameState()
+	var moves []game.Move
+	for len(moves) < plies {
+		legal := game.LegalMoves(&state)
+		if len(legal) == 0 {
+			break
+		}
+		move := legal[rng.Intn(len(legal))]
+		next := state
+		game.ApplyMove(&next, move)
+		if next.IsGameOver {
+			break
+		}
+		state = next
+		moves = append(moves, move)
+	}
+	return moves
+}
+
+// playGame 從開局走完一局，玩家ID記錄為引擎名稱
+// 引擎出錯或走出非法移動時判其負，並返回出錯記錄；只有開局無法重現時才返回錯誤
+func playGame(tiger, goat Player, opening []game.Move, seed int64, options Options) (*game.Game, *Forfeit, error) {
+	g := game.NewMatchedGame(tiger.Name, goat.Name, options.TimeControl, false)
+	g.Seed = seed
+	for _, move := range opening {
+		if err := g.MakeMove(move); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	var forfeit *Forfeit
+	for !g.State.IsGameOver && len(g.History) < options.MaxPlies {
+		side := g.State.CurrentTurn
+		player := goat
+		if side == game.Tiger {
+			player = tiger
+		}
+		move, err := player.Engine.CalculateNextMove(g)
+		if err == nil && move == nil {
+			break
+		}
+		if err == nil {
+			if moveErr := g.MakeMove(*move); moveErr != nil {
+				err = fmt.Errorf("illegal move %+v: %w", *move, moveErr)
+			}
+		}
+		if err != nil {
+			forfeit = &Forfeit{Player: player.Name, Err: err}
+			g.State.Winner = game.Opponent(side)
+			break
+		}
+	}
+
+	now := time.Now()
+	g.State.IsGameOver = true
+	g.FinishedAt = &now
+	return g, forfeit, nil
+}
+
+// Score 返回 A 的得分率
+func (r *Report) Score() float64 {
+	n := r.Wins + r.Draws + r.Losses
+	if n == 0 {
+		return 0.5
+	}
+	return (float64(r.Wins) + float64(r.Draws)/2) / float64(n)
+}
+
+// Elo 返回 A 相對 B 的等級分差及 95% 信賴區間的半寬
+// 得分率為 0 或 1 時等級分差及區間均為無窮大，信賴區間超出 0-1 時半寬亦為無窮大
+func (r *Report) Elo() (diff, margin float64) {
+	n := float64(r.Wins + r.Draws + r.Losses)
+	if n == 0 {
+		return 0, 0
+	}
+	p := r.Score()
+	if p <= 0 || p >= 1 {
+		return eloFromScore(p), math.Inf(1)
+	}
+	variance := (float64(r.Wins)*(1-p)*(1-p) +
+		float64(r.Draws)*(0.5-p)*(0.5-p) +
+		float64(r.Losses)*p*p) / n
+	stderr := math.Sqrt(variance / n)
+
+	low := eloFromScore(p - 1.96*stderr)
+	high := eloFromScore(p + 1.96*stderr)
+	return eloFromScore(p), (high - low) / 2
+}
+
+// eloFromScore 將得分率轉換為等級分差
+func eloFromScore(p float64) float64 {
+	if p <= 0 {
+		return math.Inf(-1)
+	}
+	if p >= 1 {
+		return math.Inf(1)
+	}
+	return 400 * math.Log10(p/(1-p))
+}
@@ -0,0 +1,96 @@
+package arena
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/nelawu/BagchalGolang/internal/domain/game"
+)
+
+func TestScoreAndElo(t *testing.T) {
+	inf := math.Inf(1)
+	tests := []struct {
+		name                string
+		wins, draws, losses int
+		score, diff, margin float64
+	}{
+		{"no games", 0, 0, 0, 0.5, 0, 0},
+		{"known result", 60, 20, 20, 0.7, 147.19, 66.01},
+		{"even", 10, 0, 10, 0.5, 0, 163.32},
+		{"even with draws", 5, 10, 5, 0.5, 0, 111.33},
+		{"all wins", 10, 0, 0, 1, inf, inf},
+		{"all losses", 0, 0, 10, 0, math.Inf(-1), inf},
+		{"interval below 0%", 1, 0, 9, 0.1, -381.70, inf},
+	}
+	for _, tt := range tests {
+		r := &Report{Wins: tt.wins, Draws: tt.draws, Losses: tt.losses}
+		if got := r.Score(); math.Abs(got-tt.score) > 1e-9 {
+			t.Errorf("%s: Score = %v, want %v", tt.name, got, tt.score)
+		}
+		diff, margin := r.Elo()
+		if !approx(diff, tt.diff) || !approx(margin, tt.margin) {
+			t.Errorf("%s: Elo = %.2f ± %.2f, want %.2f ± %.2f", tt.name, diff, margin, tt.diff, tt.margin)
+		}
+	}
+}
+
+// approx 比較到小數點後兩位，無窮大須完全相同
+func approx(got, want float64) bool {
+	if math.IsInf(want, 0) {
+		return got == want
+	}
+	return math.Abs(got-want) < 0.01
+}
+
+// firstMove 總是走第一個合法移動的引擎
+type firstMove struct{}
+
+func (firstMove) CalculateNextMove(g *game.Game) (*game.Move, error) {
+	moves := game.LegalMoves(&g.State)
+	if len(moves) == 0 {
+		return nil, nil
+	}
+	return &moves[0], nil
+}
+
+// failing 在指定步數後出錯或走出非法移動的引擎
+type failing struct {
+	after   int
+	illegal bool
+}
+
+func (f failing) CalculateNextMove(g *game.Game) (*game.Move, error) {
+	if len(g.History) < f.after {
+		return firstMove{}.CalculateNextMove(g)
+	}
+	if f.illegal {
+		return &game.Move{From: game.Position{X: 0, Y: 0}, To: game.Position{X: 4, Y: 4}, PieceType: g.State.CurrentTurn}, nil
+	}
+	return nil, errors.New("engine crashed")
+}
+
+func TestEngineErrorForfeitsGame(t *testing.T) {
+	for _, engine := range []failing{{after: 6}, {after: 6, illegal: true}} {
+		a := Player{Name: "good", Engine: firstMove{}}
+		b := Player{Name: "bad", Engine: engine}
+		report, err := Play(a, b, Options{Games: 4, RandomPlies: 2, MaxPlies: 200, Seed: 1}, nil)
+		if err != nil {
+			t.Fatalf("illegal=%v: Play aborted: %v", engine.illegal, err)
+		}
+		if report.Wins != 4 || report.Losses != 0 || report.Draws != 0 {
+			t.Errorf("illegal=%v: report %d/%d/%d, want 4 wins", engine.illegal, report.Wins, report.Draws, report.Losses)
+		}
+		if len(report.Games) != 4 || len(report.Forfeits) != 4 {
+			t.Fatalf("illegal=%v: %d games, %d forfeits", engine.illegal, len(report.Games), len(report.Forfeits))
+		}
+		for i, forfeit := range report.Forfeits {
+			if forfeit.Game != i || forfeit.Player != "bad" || forfeit.Err == nil {
+				t.Errorf("illegal=%v: forfeit %d = %+v", engine.illegal, i, forfeit)
+			}
+			if g := report.Games[i]; !g.State.IsGameOver || g.State.Winner != g.SideOf("good") {
+				t.Errorf("illegal=%v: game %d winner %d", engine.illegal, i, g.State.Winner)
+			}
+		}
+	}
+}
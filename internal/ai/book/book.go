@@ -0,0 +1,189 @@
+package book
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"math/rand"
+	"os"
+	"sort"
+	"sync"
+
+	"github.com/nelawu/BagchalGolang/internal/domain/game"
+)
+
+var ErrInvalidBook = errors.New("invalid opening book")
+
+// Entry 表示書中某局面的一個候選移動，移動以標準形局面的座標記錄
+type Entry struct {
+	Move   game.Move `json:"move"`
+	Weight int       `json:"weight"` // 權重越大越常被選中
+}
+
+// Book 放置階段的開局庫，以對稱標準形為鍵，對稱的局面共用同一組條目
+type Book struct {
+	mu      sync.RWMutex
+	entries map[string][]Entry
+}
+
+// New 創建空的開局庫
+func New() *Book {
+	return &Book{entries: make(map[string][]Entry)}
+}
+
+// InBook 檢查局面是否屬於開局庫涵蓋的放置階段
+func InBook(state *game.GameState) bool {
+	return !state.IsGameOver && state.GoatsInHand > 0
+}
+
+// Add 為局面增加一個候選移動的權重，已存在時累加
+func (b *Book) Add(state *game.GameState, move game.Move, weight int) {
+	if weight <= 0 || !InBook(state) {
+		return
+	}
+
+	key, sym := game.CanonicalKey(state)
+	canonical := sym.ApplyMove(move)
+	canonical.Capture = nil
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	entries := b.entries[key]
+	for i := range entries {
+		if sameMove(entries[i].Move, canonical) {
+			entries[i].Weight += weight
+			return
+		}
+	}
+	b.entries[key] = append(entries, Entry{Move: canonical, Weight: weight})
+}
+
+// Entries 返回局面的候選移動，已變換回局面本身的座標並按權重排序
+func (b *Book) Entries(state *game.GameState) []Entry {
+	if !InBook(state) {
+		return nil
+	}
+	key, sym := game.CanonicalKey(state)
+	inverse := sym.Inverse()
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	var entries []Entry
+	for _, entry := range b.entries[key] {
+		// 變換後補全吃子資訊，並排除不合法的條目
+		move, ok := game.FindLegalMove(state, inverse.ApplyMove(entry.Move))
+		if !ok {
+			continue
+		}
+		entries = append(entries, Entry{Move: move, Weight: entry.Weight})
+	}
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].Weight > entries[j].Weight })
+	return entries
+}
+
+// Probe 按權重隨機選擇局面的一個書中移動，rng 為 nil 時使用全局隨機源
+func (b *Book) Probe(state *game.GameState, rng *rand.Rand) (game.Move, bool) {
+	entries := b.Entries(state)
+	total := 0
+	for _, entry := range entries {
+		total += entry.Weight
+	}
+	if total == 0 {
+		return game.Move{}, false
+	}
+
+	var pick int
+	if rng != nil {
+		pick = rng.Intn(total)
+	} else {
+		pick = rand.Intn(total)
+	}
+	for _, entry := range entries {
+		if pick < entry.Weight {
+			return entry.Move, true
+		}
+		pick -= entry.Weight
+	}
+	return entries[len(entries)-1].Move, true
+}
+
+// Size 返回書中的局面數
+func (b *Book) Size() int {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return len(b.entries)
+}
+
+// bookFile 開局庫文件格式
+type bookFile struct {
+	Positions []positionEntries `json:"positions"`
+}
+
+type positionEntries struct {
+	Key     string  `json:"key"`
+	Entries []Entry `json:"entries"`
+}
+
+// Save 以 JSON 格式寫出開局庫
+func (b *Book) Save(w io.Writer) error {
+	b.mu.RLock()
+	file := bookFile{Positions: make([]positionEntries, 0, len(b.entries))}
+	for key, entries := range b.entries {
+		file.Positions = append(file.Positions, positionEntries{Key: key, Entries: entries})
+	}
+	b.mu.RUnlock()
+
+	sort.Slice(file.Positions, func(i, j int) bool { return file.Positions[i].Key < file.Positions[j].Key })
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(file)
+}
+
+// SaveFile 將開局庫寫入文件
+func (b *Book) SaveFile(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := b.Save(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// Load 讀取 JSON 格式的開局庫
+func Load(r io.Reader) (*Book, error) {
+	var file bookFile
+	if err := json.NewDecoder(r).Decode(&file); err != nil {
+		return nil, ErrInvalidBook
+	}
+
+	b := New()
+	for _, position := range file.Positions {
+		for _, entry := range position.Entries {
+			if entry.Weight <= 0 {
+				return nil, ErrInvalidBook
+			}
+		}
+		b.entries[position.Key] = append(b.entries[position.Key], position.Entries...)
+	}
+	return b, nil
+}
+
+// LoadFile 從文件讀取開局庫
+func LoadFile(path string) (*Book, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return Load(f)
+}
+
+// sameMove 檢查兩步棋的起點、終點與棋子是否相同
+func sameMove(a, b game.Move) bool {
+	return a.From == b.From && a.To == b.To && a.PieceType == b.PieceType
+}
@@ -0,0 +1,91 @@
+package book
+
+import (
+	"math/rand"
+
+	"github.com/nelawu/BagchalGolang/internal/domain/game"
+)
+
+// 按對局結果為走棋一方的移動加權：勝局的移動最值得參考，敗局的移動不收錄
+const (
+	winWeight  = 2
+	drawWeight = 1
+)
+
+// AddGame 將一局已結束遊戲在放置階段的移動加入開局庫
+func (b *Book) AddGame(g *game.Game) {
+	if !g.State.IsGameOver {
+		return
+	}
+
+	state := game.NewGameState()
+	for _, move := range g.History {
+		if !InBook(&state) {
+			return
+		}
+		b.Add(&state, move, resultWeight(g.State.Winner, move.PieceType))
+		game.ApplyMove(&state, move)
+	}
+}
+
+// BuildFromGames 從已結束的遊戲建立開局庫
+func BuildFromGames(games []*game.Game) *Book {
+	b := New()
+	for _, g := range games {
+		b.AddGame(g)
+	}
+	return b
+}
+
+// BuildFromSelfPlay 讓引擎自我對弈指定局數並建立開局庫
+// 每局前 randomPlies 步隨機，見 SelfPlay；maxPlies 限制每局的步數，超過時判和
+func BuildFromSelfPlay(engine game.AIEngine, games, randomPlies, maxPlies int, rng *rand.Rand) (*Book, error) {
+	b := New()
+	for i := 0; i < games; i++ {
+		g, err := SelfPlay(engine, randomPlies, maxPlies, rng)
+		if err != nil {
+			return nil, err
+		}
+		b.AddGame(g)
+	}
+	return b, nil
+}
+
+// SelfPlay 讓引擎與自己對弈一局，超過 maxPlies 步時判和
+// 高難度的引擎是確定性的，每局都會重複同一對局，因此前 randomPlies 步隨機走。
+// 隨機的移動同樣收錄，其權重來自之後由引擎下完的對局結果，多局累積後好的開局移動權重較高
+func SelfPlay(engine game.AIEngine, randomPlies, maxPlies int, rng *rand.Rand) (*game.Game, error) {
+	g := game.NewMatchedGame(game.AIPlayerID(0), game.AIPlayerID(0), nil, false)
+	g.Seed = rng.Int63()
+	for len(g.History) < maxPlies && !g.State.IsGameOver {
+		var move *game.Move
+		if len(g.History) < randomPlies {
+			moves := game.LegalMoves(&g.State)
+			move = &moves[rng.Intn(len(moves))]
+		} else {
+			var err error
+			if move, err = engine.CalculateNextMove(g); err != nil {
+				return nil, err
+			}
+			if move == nil {
+				break
+			}
+		}
+		if err := g.MakeMove(*move); err != nil {
+			return nil, err
+		}
+	}
+	g.State.IsGameOver = true
+	return g, nil
+}
+
+// resultWeight 返回對局結果對走棋一方的權重
+func resultWeight(winner, side game.PieceType) int {
+	switch winner {
+	case side:
+		return winWeight
+	case game.Empty:
+		return drawWeight
+	}
+	return 0
+}
@@ -0,0 +1,39 @@
+package ai
+
+import (
+	"time"
+
+	"github.com/nelawu/BagchalGolang/internal/domain/game"
+)
+
+// 每步思考時間的上下限
+const (
+	MinMoveTime = 50 * time.Millisecond
+	MaxMoveTime = 10 * time.Second
+
+	// movesToGo 按時間控制分配思考時間時，假設AI一方還需要下的步數
+	movesToGo = 30
+)
+
+// levelMoveTime 無時間控制時各難度每步的思考時間
+var levelMoveTime = map[int]time.Duration{
+	1: 100 * time.Millisecond,
+	2: 500 * time.Millisecond,
+	3: 3 * time.Second,
+}
+
+// MoveBudget 返回輪到的AI一方本步的思考時間
+// 有時間控制時將初始時間平均分配到預計步數並加上大部分加秒，否則按AI難度決定
+func MoveBudget(g *game.Game) time.Duration {
+	if tc := g.TimeControl; tc != nil && (tc.InitialSeconds > 0 || tc.IncrementSeconds > 0) {
+		budget := time.Duration(tc.InitialSeconds)*time.Second/movesToGo +
+			time.Duration(tc.IncrementSeconds)*time.Second*3/4
+		return max(MinMoveTime, min(MaxMoveTime, budget))
+	}
+
+	level, _ := g.SideAILevel(g.State.CurrentTurn)
+	if budget, ok := levelMoveTime[level]; ok {
+		return budget
+	}
+	return levelMoveTime[2]
+}
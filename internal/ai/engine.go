@@ -2,101 +2,97 @@ package ai
 
 import (
 	"math/rand"
-	"time"
 
+	"github.com/nelawu/BagchalGolang/internal/ai/book"
+	"github.com/nelawu/BagchalGolang/internal/ai/tablebase"
 	"github.com/nelawu/BagchalGolang/internal/domain/game"
 )
 
+// DefaultSearchDepth 困難難度的預設深度上限，通常在此之前思考時間已用完
+const DefaultSearchDepth = MaxDepth
+
 type Engine struct {
-	difficulty int
+	difficulty  int
+	searchDepth int                  // 困難難度的 alpha-beta 搜索深度上限
+	book        *book.Book           // 放置階段的開局庫，可為空
+	tablebase   *tablebase.Tablebase // 移動階段的殘局庫，可為空
+	table       *TranspositionTable  // 置換表，可為空
+	weights     Weights              // 靜態評估的權重
+	threads     int                  // 並行搜索的線程數
 }
 
 func NewEngine(difficulty int) *Engine {
 	return &Engine{
-		difficulty: difficulty,
+		difficulty:  difficulty,
+		searchDepth: DefaultSearchDepth,
+		weights:     DefaultWeights(),
+		threads:     1,
 	}
 }
 
+// SetSearchDepth 設置困難難度的搜索深度上限
+func (e *Engine) SetSearchDepth(depth int) {
+	e.searchDepth = max(1, min(MaxDepth, depth))
+}
+
+// SetOpeningBook 設置開局庫，困難難度的AI在放置階段優先使用書中移動
+func (e *Engine) SetOpeningBook(b *book.Book) {
+	e.book = b
+}
+
+// SetTablebase 設置殘局庫，困難難度的AI在移動階段按殘局庫完美對弈
+func (e *Engine) SetTablebase(tb *tablebase.Tablebase) {
+	e.tablebase = tb
+}
+
+// SetTranspositionTable 設置置換表，同一置換表可供多個引擎及多局遊戲共用
+// 搜索結果在各步之間保留，下一步的搜索可直接利用
+func (e *Engine) SetTranspositionTable(table *TranspositionTable) {
+	e.table = table
+}
+
+// SetWeights 設置靜態評估的權重
+// 置換表中的分數依賴權重，權重不同的引擎不應共用置換表
+func (e *Engine) SetWeights(weights Weights) {
+	e.weights = weights
+}
+
+// SetThreads 設置並行搜索的線程數，多於一個線程時需同時設置置換表才會並行
+// 單線程且置換表為空時，相同局面與限制的搜索結果完全可重現
+func (e *Engine) SetThreads(threads int) {
+	e.threads = max(1, threads)
+}
+
+// Explain 分解局面的靜態評估，列出每個特徵的貢獻
+func (e *Engine) Explain(state game.GameState) *Explanation {
+	return e.weights.Explain(&state)
+}
+
+// MoveRand 返回AI本步專用的隨機數生成器，由遊戲的種子及已走步數決定
+// 同一種子的遊戲只要之前的走法相同，AI的隨機選擇就完全相同
+func MoveRand(g *game.Game) *rand.Rand {
+	seed := uint64(g.Seed) + uint64(len(g.History))*0x9e3779b97f4a7c15
+	return rand.New(rand.NewSource(int64(seed)))
+}
+
 // CalculateNextMove 計算AI的下一步移動
 func (e *Engine) CalculateNextMove(g *game.Game) (*game.Move, error) {
-	// TODO: 實現更智能的AI邏輯
-	// 目前僅實現一個簡單的隨機移動策略
-
-	// 初始化隨機數生成器
-	rand.Seed(time.Now().UnixNano())
-
-	// 確定當前是虎方還是羊方
-	isGoat := g.State.CurrentTurn == game.Goat
-
-	var validMoves []game.Move
-
-	// 如果是羊方且還有羊可以放置
-	if isGoat && g.State.GoatsInHand > 0 {
-		// 收集所有可能的放置位置
-		for y := 0; y < game.BoardSize; y++ {
-			for x := 0; x < game.BoardSize; x++ {
-				if g.State.Board[y][x] == game.Empty {
-					move := game.Move{
-						From:      game.Position{X: x, Y: y},
-						To:        game.Position{X: x, Y: y},
-						PieceType: game.Goat,
-					}
-					validMoves = append(validMoves, move)
-				}
-			}
-		}
-	} else {
-		// 收集所有可能的移動
-		pieceType := game.Tiger
-		if isGoat {
-			pieceType = game.Goat
-		}
+	// 每步使用由遊戲種子決定的隨機數生成器，使遊戲可以重現
+	rng := MoveRand(g)
 
-		// 遍歷棋盤尋找己方棋子
-		for y := 0; y < game.BoardSize; y++ {
-			for x := 0; x < game.BoardSize; x++ {
-				if g.State.Board[y][x] == pieceType {
-					// 檢查所有可能的移動方向
-					directions := []struct{ dx, dy int }{
-						{-1, -1}, {-1, 0}, {-1, 1},
-						{0, -1}, {0, 1},
-						{1, -1}, {1, 0}, {1, 1},
-					}
-
-					for _, d := range directions {
-						// 檢查普通移動
-						newX, newY := x+d.dx, y+d.dy
-						if isValidPosition(newX, newY) {
-							move := game.Move{
-								From:      game.Position{X: x, Y: y},
-								To:        game.Position{X: newX, Y: newY},
-								PieceType: pieceType,
-							}
-							if isValidMove(g, move) {
-								validMoves = append(validMoves, move)
-							}
-						}
-
-						// 如果是虎，還要檢查吃子移動
-						if pieceType == game.Tiger {
-							jumpX, jumpY := x+2*d.dx, y+2*d.dy
-							if isValidPosition(jumpX, jumpY) {
-								move := game.Move{
-									From:      game.Position{X: x, Y: y},
-									To:        game.Position{X: jumpX, Y: jumpY},
-									PieceType: game.Tiger,
-								}
-								if isValidMove(g, move) {
-									// 優先考慮吃子移動
-									validMoves = append(validMoves, move)
-								}
-							}
-						}
-					}
-				}
-			}
+	// 先查開局庫，開局庫只用於困難難度，以免中等難度過強
+	if e.book != nil && e.difficulty >= 3 {
+		if move, ok := e.book.Probe(&g.State, rng); ok {
+			return &move, nil
 		}
 	}
+	if e.tablebase != nil && e.difficulty >= 3 {
+		if move, _, _, ok := e.tablebase.BestMove(&g.State); ok {
+			return &move, nil
+		}
+	}
+
+	validMoves := game.LegalMoves(&g.State)
 
 	// 如果沒有有效的移動，返回錯誤
 	if len(validMoves) == 0 {
@@ -107,7 +103,7 @@ func (e *Engine) CalculateNextMove(g *game.Game) (*game.Move, error) {
 	var selectedMove game.Move
 	switch e.difficulty {
 	case 1: // 簡單：完全隨機
-		selectedMove = validMoves[rand.Intn(len(validMoves))]
+		selectedMove = validMoves[rng.Intn(len(validMoves))]
 	case 2: // 中等：優先選擇吃子移動
 		// 將吃子移動放在前面
 		var captureMoves []game.Move
@@ -117,66 +113,19 @@ func (e *Engine) CalculateNextMove(g *game.Game) (*game.Move, error) {
 			}
 		}
 		if len(captureMoves) > 0 {
-			selectedMove = captureMoves[rand.Intn(len(captureMoves))]
+			selectedMove = captureMoves[rng.Intn(len(captureMoves))]
 		} else {
-			selectedMove = validMoves[rand.Intn(len(validMoves))]
+			selectedMove = validMoves[rng.Intn(len(validMoves))]
 		}
-	case 3: // 困難：TODO: 實現更智能的策略
-		// 目前與中等難度相同
-		selectedMove = validMoves[rand.Intn(len(validMoves))]
+	case 3: // 困難：在思考時間內逐層加深的 alpha-beta 搜索
+		result := e.Analyze(g.State, Limits{Depth: e.searchDepth, MoveTime: MoveBudget(g)})
+		if result.BestMove == nil {
+			return nil, nil
+		}
+		selectedMove = *result.BestMove
 	default:
-		selectedMove = validMoves[rand.Intn(len(validMoves))]
+		selectedMove = validMoves[rng.Intn(len(validMoves))]
 	}
 
 	return &selectedMove, nil
 }
-
-// isValidPosition 檢查位置是否在棋盤範圍內
-func isValidPosition(x, y int) bool {
-	return x >= 0 && x < game.BoardSize && y >= 0 && y < game.BoardSize
-}
-
-// isValidMove 檢查移動是否合法
-func isValidMove(g *game.Game, move game.Move) bool {
-	// 檢查目標位置是否為空
-	if g.State.Board[move.To.Y][move.To.X] != game.Empty {
-		return false
-	}
-
-	// 如果是放置羊的階段
-	if move.PieceType == game.Goat && g.State.GoatsInHand > 0 {
-		return move.From.X == move.To.X && move.From.Y == move.To.Y
-	}
-
-	// 計算移動距離
-	dx := abs(move.To.X - move.From.X)
-	dy := abs(move.To.Y - move.From.Y)
-
-	// 正常移動：只能移動到相鄰的點
-	if dx <= 1 && dy <= 1 {
-		return true
-	}
-
-	// 虎吃羊：檢查是否是有效的跳躍
-	if move.PieceType == game.Tiger && dx <= 2 && dy <= 2 {
-		// 計算中間位置
-		midX := (move.From.X + move.To.X) / 2
-		midY := (move.From.Y + move.To.Y) / 2
-
-		// 檢查中間是否有羊
-		if g.State.Board[midY][midX] == game.Goat {
-			move.Capture = &game.Position{X: midX, Y: midY}
-			return true
-		}
-	}
-
-	return false
-}
-
-// abs 返回整數的絕對值
-func abs(x int) int {
-	if x < 0 {
-		return -x
-	}
-	return x
-} 
\ No newline at end of file
@@ -0,0 +1,39 @@
+package ai
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/nelawu/BagchalGolang/internal/domain/game"
+)
+
+// playSeeded 讓同一難度的AI執雙方下完一局，返回移動歷史
+func playSeeded(t *testing.T, level int, seed int64) []game.Move {
+	t.Helper()
+	engine := NewEngine(level)
+	g := game.NewGame("tester", false, 0)
+	g.Seed = seed
+	for ply := 0; ply < 200 && !g.State.IsGameOver; ply++ {
+		move, err := engine.CalculateNextMove(g)
+		if err != nil {
+			t.Fatalf("level %d ply %d: %v", level, ply, err)
+		}
+		if err := g.MakeMove(*move); err != nil {
+			t.Fatalf("level %d ply %d: illegal move %+v: %v", level, ply, *move, err)
+		}
+	}
+	return g.History
+}
+
+func TestSeedReplaysGame(t *testing.T) {
+	for _, level := range []int{1, 2} {
+		first := playSeeded(t, level, 42)
+		replay := playSeeded(t, level, 42)
+		if !reflect.DeepEqual(first, replay) {
+			t.Errorf("level %d: replay with the same seed diverged", level)
+		}
+		if other := playSeeded(t, level, 43); reflect.DeepEqual(first, other) {
+			t.Errorf("level %d: different seeds produced the same game", level)
+		}
+	}
+}
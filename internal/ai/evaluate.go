@@ -0,0 +1,220 @@
+package ai
+
+import (
+	"encoding/json"
+	"os"
+
+	"github.com/nelawu/BagchalGolang/internal/domain/game"
+)
+
+// Features 局面的評估特徵
+type Features struct {
+	CapturedGoats   int // 已被吃掉的羊
+	TigerMobility   int // 虎可走的移動數
+	TrappedTigers   int // 無法移動的虎
+	GoatsThreatened int // 虎下一步可吃的羊
+	GoatsInHand     int // 尚未放置的羊
+	ProtectedGoats  int // 在任何方向都不可能被跳吃的羊（兩端有羊或棋盤邊緣保護）
+}
+
+// featureCount 特徵數量
+const featureCount = 6
+
+// FeatureNames 各特徵的名稱，順序與 Features.Values 及 Weights.Values 一致
+var FeatureNames = [featureCount]string{
+	"capturedGoats",
+	"tigerMobility",
+	"trappedTigers",
+	"goatsThreatened",
+	"goatsInHand",
+	"protectedGoats",
+}
+
+// Values 按 FeatureNames 的順序返回特徵值
+func (f Features) Values() [featureCount]int {
+	return [featureCount]int{
+		f.CapturedGoats,
+		f.TigerMobility,
+		f.TrappedTigers,
+		f.GoatsThreatened,
+		f.GoatsInHand,
+		f.ProtectedGoats,
+	}
+}
+
+// Weights 各特徵的權重，以虎方視角計算，單位為「百分之一隻羊」
+type Weights struct {
+	CapturedGoats   int `json:"capturedGoats"`
+	TigerMobility   int `json:"tigerMobility"`
+	TrappedTigers   int `json:"trappedTigers"`
+	GoatsThreatened int `json:"goatsThreatened"`
+	GoatsInHand     int `json:"goatsInHand"`
+	ProtectedGoats  int `json:"protectedGoats"`
+}
+
+// DefaultWeights 返回手工設定的預設權重
+func DefaultWeights() Weights {
+	return Weights{
+		CapturedGoats:   100,
+		TigerMobility:   2,
+		TrappedTigers:   -40,
+		GoatsThreatened: 30,
+		GoatsInHand:     2,
+		ProtectedGoats:  -5,
+	}
+}
+
+// Values 按 FeatureNames 的順序返回權重
+func (w Weights) Values() [featureCount]int {
+	return Features(w).Values()
+}
+
+// WeightsFromValues 按 FeatureNames 的順序構造權重
+func WeightsFromValues(values [featureCount]int) Weights {
+	return Weights{
+		CapturedGoats:   values[0],
+		TigerMobility:   values[1],
+		TrappedTigers:   values[2],
+		GoatsThreatened: values[3],
+		GoatsInHand:     values[4],
+		ProtectedGoats:  values[5],
+	}
+}
+
+// LoadWeights 從 JSON 文件讀取權重，文件中未出現的特徵沿用預設權重
+func LoadWeights(path string) (Weights, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Weights{}, err
+	}
+	weights := DefaultWeights()
+	if err := json.Unmarshal(data, &weights); err != nil {
+		return Weights{}, err
+	}
+	return weights, nil
+}
+
+// SaveWeights 將權重寫入 JSON 文件
+func SaveWeights(path string, weights Weights) error {
+	data, err := json.MarshalIndent(weights, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// ExtractFeatures 計算局面的評估特徵，與輪到哪一方無關
+func ExtractFeatures(state *game.GameState) Features {
+	tigerView := *state
+	tigerView.CurrentTurn = game.Tiger
+	tigerView.IsGameOver = false
+
+	features := Features{
+		CapturedGoats:  state.CapturedGoats,
+		GoatsInHand:    state.GoatsInHand,
+		ProtectedGoats: protectedGoats(state),
+	}
+	movable := make(map[game.Position]bool)
+	threatened := make(map[game.Position]bool)
+	for _, move := range game.LegalMoves(&tigerView) {
+		movable[move.From] = true
+		features.TigerMobility++
+		if move.Capture != nil {
+			threatened[*move.Capture] = true
+		}
+	}
+	features.GoatsThreatened = len(threatened)
+	features.TrappedTigers = game.MaxTigers - len(movable)
+	return features
+}
+
+// 經過一點的直線，每條線只取一個方向；斜線只經過 (x+y) 為偶數的點
+var (
+	orthogonalLines = [][2]int{{1, 0}, {0, 1}}
+	diagonalLines   = [][2]int{{1, 1}, {1, -1}}
+)
+
+// protectedGoats 計算不可能被跳吃的羊：經過它的每條線上，兩端至少有一端在棋盤外，或兩端都有棋子
+func protectedGoats(state *game.GameState) int {
+	occupied := func(x, y int) (inside, filled bool) {
+		if x < 0 || x >= game.BoardSize || y < 0 || y >= game.BoardSize {
+			return false, false
+		}
+		return true, state.Board[y][x] != game.Empty
+	}
+
+	count := 0
+	for y := 0; y < game.BoardSize; y++ {
+		for x := 0; x < game.BoardSize; x++ {
+			if state.Board[y][x] != game.Goat {
+				continue
+			}
+			lines := orthogonalLines
+			if (x+y)%2 == 0 {
+				lines = append(lines[:len(lines):len(lines)], diagonalLines...)
+			}
+
+			protected := true
+			for _, d := range lines {
+				insideA, filledA := occupied(x+d[0], y+d[1])
+				insideB, filledB := occupied(x-d[0], y-d[1])
+				if insideA && insideB && (!filledA || !filledB) {
+					protected = false
+					break
+				}
+			}
+			if protected {
+				count++
+			}
+		}
+	}
+	return count
+}
+
+// Evaluate 以預設權重靜態評估局面，正數對虎有利，負數對羊有利
+func Evaluate(state *game.GameState) int {
+	return DefaultWeights().Evaluate(state)
+}
+
+// Evaluate 以此權重靜態評估局面，正數對虎有利，負數對羊有利
+func (w Weights) Evaluate(state *game.GameState) int {
+	features := ExtractFeatures(state).Values()
+	weights := w.Values()
+	score := 0
+	for i := range features {
+		score += features[i] * weights[i]
+	}
+	return score
+}
+
+// Contribution 單個特徵對評估分數的貢獻
+type Contribution struct {
+	Feature string `json:"feature"`
+	Value   int    `json:"value"`
+	Weight  int    `json:"weight"`
+	Score   int    `json:"score"` // Value * Weight
+}
+
+// Explanation 靜態評估的分解，用於調試權重
+type Explanation struct {
+	Contributions []Contribution `json:"contributions"`
+	TigerScore    int            `json:"tigerScore"` // 各特徵貢獻之和
+}
+
+// Explain 分解局面的靜態評估，列出每個特徵的值、權重及貢獻
+func (w Weights) Explain(state *game.GameState) *Explanation {
+	features := ExtractFeatures(state).Values()
+	weights := w.Values()
+	explanation := &Explanation{Contributions: make([]Contribution, 0, featureCount)}
+	for i, name := range FeatureNames {
+		score := features[i] * weights[i]
+		explanation.Contributions = append(explanation.Contributions, Contribution{
+			Feature: name,
+			Value:   features[i],
+			Weight:  weights[i],
+			Score:   score,
+		})
+		explanation.TigerScore += score
+	}
+	return explanation
+}
@@ -0,0 +1,223 @@
+package mcts
+
+import (
+	"math"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/nelawu/BagchalGolang/internal/ai"
+	"github.com/nelawu/BagchalGolang/internal/domain/game"
+)
+
+// Config 蒙地卡羅樹搜索參數，Iterations 與 MoveTime 任一達到即停止
+// 對局中的時間預算來自 ai.MoveBudget，MoveTime 為其上限
+type Config struct {
+	Iterations      int           // 模擬次數上限，0 表示不限
+	MoveTime        time.Duration // 時間預算，0 表示不限
+	Exploration     float64       // UCT 探索常數
+	Policy          PlayoutPolicy // 模擬策略
+	MaxPlayoutPlies int           // 模擬的最大步數，超過時以靜態評估判定
+	Weights         ai.Weights    // 模擬超過步數上限時使用的評估權重
+	Seed            int64         // Search 使用的隨機數種子，0 表示按時間生成；對局中按遊戲種子
+}
+
+// DefaultConfig 返回預設參數
+func DefaultConfig() Config {
+	return Config{
+		MoveTime:        ai.MaxMoveTime,
+		Exploration:     math.Sqrt2,
+		Policy:          CapturePolicy,
+		MaxPlayoutPlies: 100,
+		Weights:         ai.DefaultWeights(),
+	}
+}
+
+// Engine 以 UCT 蒙地卡羅樹搜索選擇移動，實現 game.AIEngine
+type Engine struct {
+	config Config
+
+	mu  sync.Mutex // rand.Rand 不是並發安全的
+	rng *rand.Rand // Search 及 SearchFor 使用，對局中每步另有由遊戲種子決定的生成器
+}
+
+func NewEngine(config Config) *Engine {
+	if config.Policy == nil {
+		config.Policy = CapturePolicy
+	}
+	if config.Weights == (ai.Weights{}) {
+		config.Weights = ai.DefaultWeights()
+	}
+	if config.Iterations <= 0 && config.MoveTime <= 0 {
+		config.MoveTime = DefaultConfig().MoveTime
+	}
+	seed := config.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	return &Engine{
+		config: config,
+		rng:    rand.New(rand.NewSource(seed)),
+	}
+}
+
+// Result 一次搜索的結果
+type Result struct {
+	Move       *game.Move `json:"move,omitempty"`
+	Visits     int        `json:"visits"`     // 最佳移動的訪問次數
+	WinRate    float64    `json:"winRate"`    // 最佳移動對輪到的一方的期望得分
+	Iterations int        `json:"iterations"` // 完成的模擬次數
+}
+
+// node 搜索樹的節點，value 以走入此節點的一方（父節點輪到的一方）視角累計
+type node struct {
+	state    game.GameState
+	move     game.Move
+	parent   *node
+	children []*node
+	untried  []game.Move
+	visits   int
+	value    float64
+}
+
+func newNode(state game.GameState, move game.Move, parent *node) *node {
+	return &node{
+		state:   state,
+		move:    move,
+		parent:  parent,
+		untried: game.LegalMoves(&state),
+	}
+}
+
+// CalculateNextMove 在本步的思考時間內搜索，返回訪問次數最多的移動
+// 隨機數由遊戲種子決定，但以時間為預算時模擬次數仍可能不同
+func (e *Engine) CalculateNextMove(g *game.Game) (*game.Move, error) {
+	return e.search(g.State, ai.MoveBudget(g), ai.MoveRand(g)).Move, nil
+}
+
+// Search 按設定的模擬次數及時間預算進行蒙地卡羅樹搜索
+func (e *Engine) Search(state game.GameState) *Result {
+	return e.SearchFor(state, e.config.MoveTime)
+}
+
+// SearchFor 以指定的時間預算搜索，設定的 MoveTime 為上限
+func (e *Engine) SearchFor(state game.GameState, moveTime time.Duration) *Result {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.search(state, moveTime, e.rng)
+}
+
+// search 以指定的隨機數生成器進行蒙地卡羅樹搜索
+func (e *Engine) search(state game.GameState, moveTime time.Duration, rng *rand.Rand) *Result {
+	root := newNode(state, game.Move{}, nil)
+	if len(root.untried) == 0 {
+		return &Result{}
+	}
+
+	// 一步即勝時直接走：模擬全勝的移動之間 UCT 分不出哪個勝得最快，可能一直拖延不吃第五隻羊
+	for _, move := range root.untried {
+		child := state
+		game.ApplyMove(&child, move)
+		if child.IsGameOver && child.Winner == state.CurrentTurn {
+			return &Result{Move: &move, WinRate: 1}
+		}
+	}
+
+	if e.config.MoveTime > 0 && (moveTime <= 0 || moveTime > e.config.MoveTime) {
+		moveTime = e.config.MoveTime
+	}
+	var deadline time.Time
+	if moveTime > 0 {
+		deadline = time.Now().Add(moveTime)
+	}
+
+	// 至少完成一次模擬，使根節點有子節點可選
+	iterations := 0
+	for e.config.Iterations <= 0 || iterations < e.config.Iterations {
+		if !deadline.IsZero() && iterations > 0 && iterations%64 == 0 && time.Now().After(deadline) {
+			break
+		}
+
+		// 選擇：沿 UCT 值最高的子節點下降，直到有未展開的移動
+		n := root
+		for len(n.untried) == 0 && len(n.children) > 0 {
+			n = n.selectChild(e.config.Exploration)
+		}
+
+		// 展開：隨機展開一個未嘗試的移動
+		if len(n.untried) > 0 {
+			i := rng.Intn(len(n.untried))
+			move := n.untried[i]
+			n.untried[i] = n.untried[len(n.untried)-1]
+			n.untried = n.untried[:len(n.untried)-1]
+
+			child := n.state
+			game.ApplyMove(&child, move)
+			next := newNode(child, move, n)
+			n.children = append(n.children, next)
+			n = next
+		}
+
+		// 模擬並回傳結果
+		tigerScore := e.playout(n.state, rng)
+		for ; n != nil; n = n.parent {
+			n.visits++
+			if n.parent != nil {
+				if n.parent.state.CurrentTurn == game.Tiger {
+					n.value += tigerScore
+				} else {
+					n.value += 1 - tigerScore
+				}
+			}
+		}
+		iterations++
+	}
+
+	best := root.children[0]
+	for _, child := range root.children[1:] {
+		if child.visits > best.visits {
+			best = child
+		}
+	}
+	move := best.move
+	return &Result{
+		Move:       &move,
+		Visits:     best.visits,
+		WinRate:    best.value / float64(max(best.visits, 1)),
+		Iterations: iterations,
+	}
+}
+
+// selectChild 返回 UCT 值最高的子節點
+func (n *node) selectChild(exploration float64) *node {
+	logVisits := math.Log(float64(n.visits))
+	var best *node
+	bestValue := math.Inf(-1)
+	for _, child := range n.children {
+		uct := child.value/float64(child.visits) + exploration*math.Sqrt(logVisits/float64(child.visits))
+		if uct > bestValue {
+			best, bestValue = child, uct
+		}
+	}
+	return best
+}
+
+// playout 按模擬策略下完一局，返回虎方得分（勝 1、負 0、和 0.5）
+// 超過步數上限時以靜態評估估計虎方的勝率
+func (e *Engine) playout(state game.GameState, rng *rand.Rand) float64 {
+	for ply := 0; !state.IsGameOver; ply++ {
+		if ply >= e.config.MaxPlayoutPlies {
+			return 1 / (1 + math.Exp(-float64(e.config.Weights.Evaluate(&state))/200))
+		}
+		moves := game.LegalMoves(&state)
+		game.ApplyMove(&state, e.config.Policy.Choose(&state, moves, rng))
+	}
+
+	switch state.Winner {
+	case game.Tiger:
+		return 1
+	case game.Goat:
+		return 0
+	}
+	return 0.5
+}
@@ -0,0 +1,134 @@
+package mcts
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/nelawu/BagchalGolang/internal/domain/game"
+)
+
+// midgame 返回從開局隨機走 plies 步的局面，跳過使對局結束的移動
+func midgame(seed int64, plies int) game.GameState {
+	rng := rand.New(rand.NewSource(seed))
+	state := game.NewGameState()
+	for i := 0; i < plies; i++ {
+		moves := game.LegalMoves(&state)
+		next := state
+		game.ApplyMove(&next, moves[rng.Intn(len(moves))])
+		if !next.IsGameOver {
+			state = next
+		}
+	}
+	return state
+}
+
+// isLegal 檢查移動是否為局面的合法移動
+func isLegal(state game.GameState, move *game.Move) bool {
+	if move == nil {
+		return false
+	}
+	_, ok := game.FindLegalMove(&state, *move)
+	return ok
+}
+
+func TestSearchReturnsLegalMove(t *testing.T) {
+	for _, state := range []game.GameState{game.NewGameState(), midgame(1, 10), midgame(2, 30)} {
+		for _, policy := range []PlayoutPolicy{RandomPolicy, CapturePolicy, SafePolicy} {
+			engine := NewEngine(Config{Iterations: 100, Exploration: 1.4, Policy: policy, MaxPlayoutPlies: 60, Seed: 1})
+			if result := engine.Search(state); !isLegal(state, result.Move) {
+				t.Errorf("illegal move %+v", result.Move)
+			}
+		}
+	}
+}
+
+func TestSearchTakesWinningCapture(t *testing.T) {
+	// 虎已吃 4 隻羊，(0,0) 的虎跳過 (1,0) 即勝
+	state := game.GameState{CurrentTurn: game.Tiger, GoatsInHand: 12, CapturedGoats: game.CapturesToWin - 1}
+	state.Board[0][0] = game.Tiger
+	state.Board[0][4] = game.Tiger
+	state.Board[4][0] = game.Tiger
+	state.Board[4][4] = game.Tiger
+	state.Board[0][1] = game.Goat
+	state.Board[2][2] = game.Goat
+	state.Board[3][3] = game.Goat
+	state.Board[1][4] = game.Goat
+
+	want := game.Move{From: game.Position{X: 0, Y: 0}, To: game.Position{X: 2, Y: 0}}
+	for seed := int64(1); seed <= 3; seed++ {
+		engine := NewEngine(Config{Iterations: 400, Exploration: 1.4, Policy: RandomPolicy, MaxPlayoutPlies: 60, Seed: seed})
+		result := engine.Search(state)
+		if result.Move == nil || result.Move.From != want.From || result.Move.To != want.To {
+			t.Errorf("seed %d: move %+v, want the winning capture", seed, result.Move)
+		}
+		if result.WinRate != 1 {
+			t.Errorf("seed %d: win rate %v, want 1", seed, result.WinRate)
+		}
+	}
+}
+
+func TestSeededSearchIsReproducible(t *testing.T) {
+	state := midgame(3, 24)
+	config := Config{Iterations: 300, Exploration: 1.4, Policy: CapturePolicy, MaxPlayoutPlies: 60, Seed: 42}
+	first := NewEngine(config).Search(state)
+	second := NewEngine(config).Search(state)
+	if !reflect.DeepEqual(first, second) {
+		t.Errorf("same seed gave %+v and %+v", first, second)
+	}
+
+	// 對局中的隨機數來自遊戲種子，與引擎的種子無關
+	g := game.NewGame("tester", false, 0)
+	g.Seed = 7
+	config.Seed = 0
+	a, _ := NewEngine(config).CalculateNextMove(g)
+	b, _ := NewEngine(config).CalculateNextMove(g)
+	if !reflect.DeepEqual(a, b) {
+		t.Errorf("same game seed gave %+v and %+v", a, b)
+	}
+}
+
+func TestIterationLimit(t *testing.T) {
+	state := midgame(4, 12)
+	for _, iterations := range []int{1, 63, 64, 200} {
+		engine := NewEngine(Config{Iterations: iterations, Exploration: 1.4, MaxPlayoutPlies: 60, Seed: 1})
+		if result := engine.Search(state); result.Iterations != iterations || !isLegal(state, result.Move) {
+			t.Errorf("limit %d: %d iterations, move %+v", iterations, result.Iterations, result.Move)
+		}
+	}
+}
+
+func TestMoveTimeLimit(t *testing.T) {
+	const moveTime = 50 * time.Millisecond
+	state := midgame(5, 12)
+	engine := NewEngine(Config{MoveTime: moveTime, Exploration: 1.4, MaxPlayoutPlies: 60, Seed: 1})
+
+	// 設定的 MoveTime 為 SearchFor 的上限
+	for _, budget := range []time.Duration{0, time.Minute} {
+		start := time.Now()
+		result := engine.SearchFor(state, budget)
+		elapsed := time.Since(start)
+		if elapsed < moveTime || elapsed > moveTime+time.Second {
+			t.Errorf("budget %v: searched for %v, want about %v", budget, elapsed, moveTime)
+		}
+		if result.Iterations == 0 || !isLegal(state, result.Move) {
+			t.Errorf("budget %v: %d iterations, move %+v", budget, result.Iterations, result.Move)
+		}
+	}
+
+	// 時間預算先到時不受 Iterations 限制
+	engine = NewEngine(Config{Iterations: 1 << 30, MoveTime: moveTime, Exploration: 1.4, MaxPlayoutPlies: 60, Seed: 1})
+	start := time.Now()
+	if result := engine.Search(state); result.Iterations >= 1<<30 || time.Since(start) > moveTime+time.Second {
+		t.Errorf("time limit ignored: %d iterations in %v", result.Iterations, time.Since(start))
+	}
+}
+
+func TestSearchWithoutMoves(t *testing.T) {
+	state := game.NewGameState()
+	state.IsGameOver = true
+	if result := NewEngine(Config{Iterations: 10, Seed: 1}).Search(state); result.Move != nil {
+		t.Errorf("finished game returned move %+v", result.Move)
+	}
+}
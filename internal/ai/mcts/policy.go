@@ -0,0 +1,77 @@
+package mcts
+
+import (
+	"math/rand"
+
+	"github.com/nelawu/BagchalGolang/internal/domain/game"
+)
+
+// PlayoutPolicy 在模擬對局中為輪到的一方選擇移動
+type PlayoutPolicy interface {
+	Choose(state *game.GameState, moves []game.Move, rng *rand.Rand) game.Move
+}
+
+// PolicyFunc 將普通函數轉換為 PlayoutPolicy
+type PolicyFunc func(state *game.GameState, moves []game.Move, rng *rand.Rand) game.Move
+
+func (f PolicyFunc) Choose(state *game.GameState, moves []game.Move, rng *rand.Rand) game.Move {
+	return f(state, moves, rng)
+}
+
+// RandomPolicy 完全隨機選擇移動
+var RandomPolicy = PolicyFunc(func(state *game.GameState, moves []game.Move, rng *rand.Rand) game.Move {
+	return moves[rng.Intn(len(moves))]
+})
+
+// CapturePolicy 虎方有吃子時必定吃子，其餘隨機
+// 隨機模擬中虎方常錯過吃子，導致羊方勝率被高估
+var CapturePolicy = PolicyFunc(func(state *game.GameState, moves []game.Move, rng *rand.Rand) game.Move {
+	var captures []game.Move
+	for _, move := range moves {
+		if move.Capture != nil {
+			captures = append(captures, move)
+		}
+	}
+	if len(captures) > 0 {
+		return captures[rng.Intn(len(captures))]
+	}
+	return moves[rng.Intn(len(moves))]
+})
+
+// SafePolicy 在 CapturePolicy 的基礎上，羊方優先選擇走完後虎無子可吃的移動
+// 比隨機模擬更接近實戰，但每步需多次生成合法移動，模擬速度較慢
+var SafePolicy = PolicyFunc(func(state *game.GameState, moves []game.Move, rng *rand.Rand) game.Move {
+	if state.CurrentTurn == game.Tiger {
+		return CapturePolicy(state, moves, rng)
+	}
+
+	var safe []game.Move
+	for _, move := range moves {
+		next := *state
+		game.ApplyMove(&next, move)
+		if !hasCapture(&next) {
+			safe = append(safe, move)
+		}
+	}
+	if len(safe) > 0 {
+		return safe[rng.Intn(len(safe))]
+	}
+	return moves[rng.Intn(len(moves))]
+})
+
+// hasCapture 檢查輪到的虎方是否有吃子移動
+func hasCapture(state *game.GameState) bool {
+	for _, move := range game.LegalMoves(state) {
+		if move.Capture != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// Policies 可按名稱選擇的模擬策略
+var Policies = map[string]PlayoutPolicy{
+	"random":  RandomPolicy,
+	"capture": CapturePolicy,
+	"safe":    SafePolicy,
+}
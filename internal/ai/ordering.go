@@ -0,0 +1,76 @@
+package ai
+
+import (
+	"sort"
+
+	"github.com/nelawu/BagchalGolang/internal/domain/game"
+)
+
+// squareCount 棋盤上的點數
+const squareCount = game.BoardSize * game.BoardSize
+
+// 移動排序的優先級：上一輪最佳移動 > 置換表移動 > 吃子 > 殺手移動 > 歷史分數
+const (
+	rootMovePriority = 1 << 30
+	hashMovePriority = 1 << 29
+	capturePriority  = 1 << 28
+	killerPriority   = 1 << 26
+)
+
+// orderMoves 按搜索優先級排序移動，讓剪枝更早發生
+// hashMove 為置換表記錄的最佳移動，其棋子類型未記錄，只比較起點與終點
+func (s *searcher) orderMoves(moves []game.Move, side game.PieceType, ply int, hashMove *game.Move) {
+	priorities := make([]int, len(moves))
+	for i, move := range moves {
+		switch {
+		case ply == 0 && s.rootMove != nil && sameMove(move, *s.rootMove):
+			priorities[i] = rootMovePriority
+		case hashMove != nil && move.From == hashMove.From && move.To == hashMove.To:
+			priorities[i] = hashMovePriority
+		case move.Capture != nil:
+			priorities[i] = capturePriority
+		case ply < len(s.killers) && sameMove(move, s.killers[ply][0]):
+			priorities[i] = killerPriority + 1
+		case ply < len(s.killers) && sameMove(move, s.killers[ply][1]):
+			priorities[i] = killerPriority
+		default:
+			priorities[i] = s.history[side][squareIndex(move.From)][squareIndex(move.To)]
+		}
+	}
+	sort.Sort(byPriority{moves: moves, priorities: priorities})
+}
+
+// recordCutoff 記錄造成剪枝的不吃子移動，供同層的兄弟節點及之後的搜索優先嘗試
+func (s *searcher) recordCutoff(move game.Move, side game.PieceType, depth, ply int) {
+	if move.Capture != nil {
+		return
+	}
+	if ply < len(s.killers) && !sameMove(move, s.killers[ply][0]) {
+		s.killers[ply][1] = s.killers[ply][0]
+		s.killers[ply][0] = move
+	}
+	s.history[side][squareIndex(move.From)][squareIndex(move.To)] += depth * depth
+}
+
+// squareIndex 返回位置在棋盤上的序號
+func squareIndex(p game.Position) int {
+	return p.Y*game.BoardSize + p.X
+}
+
+// sameMove 檢查兩步棋的起點、終點與棋子是否相同
+func sameMove(a, b game.Move) bool {
+	return a.From == b.From && a.To == b.To && a.PieceType == b.PieceType
+}
+
+// byPriority 按優先級從高到低排序移動
+type byPriority struct {
+	moves      []game.Move
+	priorities []int
+}
+
+func (b byPriority) Len() int           { return len(b.moves) }
+func (b byPriority) Less(i, j int) bool { return b.priorities[i] > b.priorities[j] }
+func (b byPriority) Swap(i, j int) {
+	b.moves[i], b.moves[j] = b.moves[j], b.moves[i]
+	b.priorities[i], b.priorities[j] = b.priorities[j], b.priorities[i]
+}
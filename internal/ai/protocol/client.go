@@ -0,0 +1,194 @@
+package protocol
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nelawu/BagchalGolang/internal/ai"
+	"github.com/nelawu/BagchalGolang/internal/domain/game"
+)
+
+var (
+	ErrEngineTimeout = errors.New("engine did not respond in time")
+	ErrEngineExited  = errors.New("engine process exited")
+)
+
+// 等待外部引擎回應的時間
+const (
+	handshakeTimeout = 10 * time.Second
+	readyTimeout     = 5 * time.Second
+	moveGrace        = 2 * time.Second // 超過思考時間多久仍未回應視為超時
+)
+
+// Client 啟動一個說 bpi 協議的外部引擎程序，實現 game.AIEngine
+// 同一程序同時只處理一個請求，需要並行或自動重啟時使用 Pool
+type Client struct {
+	Name string // 引擎在握手時回報的名稱
+
+	mu    sync.Mutex
+	cmd   *exec.Cmd
+	stdin io.WriteCloser
+	lines chan string // 引擎輸出的行，程序結束時關閉
+}
+
+// Start 啟動外部引擎並完成握手
+func Start(path string, args ...string) (*Client, error) {
+	cmd := exec.Command(path, args...)
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+
+	c := &Client{
+		Name:  path,
+		cmd:   cmd,
+		stdin: stdin,
+		lines: make(chan string, 64),
+	}
+	go func() {
+		scanner := bufio.NewScanner(stdout)
+		for scanner.Scan() {
+			c.lines <- scanner.Text()
+		}
+		close(c.lines)
+	}()
+
+	if err := c.send("bpi"); err != nil {
+		c.Close()
+		return nil, err
+	}
+	err = c.readUntil("bpiok", handshakeTimeout, func(line string) {
+		if name, ok := strings.CutPrefix(line, "id name "); ok {
+			c.Name = name
+		}
+	})
+	if err != nil {
+		c.Close()
+		return nil, err
+	}
+	return c, nil
+}
+
+// SetOption 設置引擎選項，並等待引擎處理完畢
+func (c *Client) SetOption(name, value string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.send(fmt.Sprintf("setoption name %s value %s", name, value)); err != nil {
+		return err
+	}
+	return c.sync()
+}
+
+// CalculateNextMove 將遊戲的移動記錄發送給引擎，在本步的思考時間內等待最佳移動
+func (c *Client) CalculateNextMove(g *game.Game) (*game.Move, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// 先同步，丟棄之前超時的請求遲到的回應
+	if err := c.sync(); err != nil {
+		return nil, err
+	}
+
+	budget := ai.MoveBudget(g)
+	if err := c.send(PositionCommand(g.History)); err != nil {
+		return nil, err
+	}
+	if err := c.send(fmt.Sprintf("go movetime %d", budget.Milliseconds())); err != nil {
+		return nil, err
+	}
+
+	var best string
+	err := c.readUntil("bestmove", budget+moveGrace, func(line string) {
+		if fields := strings.Fields(line); len(fields) >= 2 && fields[0] == "bestmove" {
+			best = fields[1]
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	if best == "none" {
+		return nil, nil
+	}
+
+	move, err := ParseMove(&g.State, best)
+	if err != nil {
+		return nil, fmt.Errorf("engine %s returned %q: %w", c.Name, best, err)
+	}
+	return &move, nil
+}
+
+// Close 請求引擎結束，逾時則強制終止
+func (c *Client) Close() error {
+	c.send("quit")
+	c.stdin.Close()
+
+	done := make(chan error, 1)
+	go func() { done <- c.cmd.Wait() }()
+	select {
+	case err := <-done:
+		return err
+	case <-time.After(readyTimeout):
+		c.cmd.Process.Kill()
+		return <-done
+	}
+}
+
+// kill 立即終止引擎程序，用於已無回應或已結束的引擎
+func (c *Client) kill() {
+	c.stdin.Close()
+	c.cmd.Process.Kill()
+	c.cmd.Wait()
+	// 排空輸出，讓讀取 goroutine 得以結束
+	go func() {
+		for range c.lines {
+		}
+	}()
+}
+
+// send 發送一行命令
+func (c *Client) send(command string) error {
+	_, err := io.WriteString(c.stdin, command+"\n")
+	return err
+}
+
+// sync 發送 isready 並等待 readyok
+func (c *Client) sync() error {
+	if err := c.send("isready"); err != nil {
+		return err
+	}
+	return c.readUntil("readyok", readyTimeout, nil)
+}
+
+// readUntil 讀取引擎輸出直到以 prefix 開頭的一行，每一行都交給 handle 處理
+func (c *Client) readUntil(prefix string, timeout time.Duration, handle func(line string)) error {
+	deadline := time.After(timeout)
+	for {
+		select {
+		case line, ok := <-c.lines:
+			if !ok {
+				return ErrEngineExited
+			}
+			if handle != nil {
+				handle(line)
+			}
+			if line == prefix || strings.HasPrefix(line, prefix+" ") {
+				return nil
+			}
+		case <-deadline:
+			return ErrEngineTimeout
+		}
+	}
+}
@@ -0,0 +1,194 @@
+// Package protocol 實現八角棋引擎的文字協議（bpi，仿照國際象棋的 UCI）
+//
+// 界面與引擎之間以換行分隔的文字命令通訊，引擎從標準輸入讀取命令、向標準輸出寫入回應。
+//
+// 界面發送的命令：
+//
+//	bpi                             握手，引擎回應 id name <名稱>、可選的 option name <選項>，最後回應 bpiok
+//	isready                         引擎處理完之前的命令後回應 readyok
+//	setoption name <選項> value <值> 設置引擎選項
+//	newgame                         開始新對局，引擎可清除之前的搜索資訊
+//	position startpos [moves <移動>...]
+//	position fen <局面> [moves <移動>...]
+//	go [movetime <毫秒>] [depth <深度>]
+//	quit                            結束引擎
+//
+// 引擎對 go 的回應：零至多行 info depth <深度> score <分數> nodes <節點數> time <毫秒> pv <移動>...，
+// 最後一行 bestmove <移動>，無子可動或最近的 position 無效時為 bestmove none。分數以輪到的一方視角表示，單位為百分之一隻羊。
+//
+// 點以列 a-e（x 0-4）及行 1-5（y 0-4）表示，如 c3 為中心點。放置羊只寫落點（c3），
+// 移動寫起點及終點（a1b2），吃子由跳躍的起終點隱含（a1a3）。
+//
+// 局面（fen）由四個以空格分隔的字段組成：棋盤、輪到的一方（t 或 g）、手上的羊數、被吃的羊數。
+// 棋盤從第 1 行起逐行以 / 分隔，T 為虎、G 為羊、數字為連續空點數，如開局為 T3T/5/5/5/T3T g 20 0。
+package protocol
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/nelawu/BagchalGolang/internal/domain/game"
+)
+
+var (
+	ErrSyntax      = errors.New("invalid protocol syntax")
+	ErrIllegalMove = errors.New("illegal move")
+)
+
+// FormatSquare 返回點的記法，如 (2, 2) 為 c3
+func FormatSquare(p game.Position) string {
+	return fmt.Sprintf("%c%d", 'a'+p.X, p.Y+1)
+}
+
+// ParseSquare 解析點的記法
+func ParseSquare(text string) (game.Position, error) {
+	if len(text) != 2 || text[0] < 'a' || text[0] >= 'a'+game.BoardSize || text[1] < '1' || text[1] >= '1'+game.BoardSize {
+		return game.Position{}, ErrSyntax
+	}
+	return game.Position{X: int(text[0] - 'a'), Y: int(text[1] - '1')}, nil
+}
+
+// FormatMove 返回移動的記法，放置只寫落點
+func FormatMove(move game.Move) string {
+	if move.From == move.To {
+		return FormatSquare(move.To)
+	}
+	return FormatSquare(move.From) + FormatSquare(move.To)
+}
+
+// ParseMove 在局面中解析移動的記法，返回補全棋子及吃子資訊的合法移動
+func ParseMove(state *game.GameState, text string) (game.Move, error) {
+	var move game.Move
+	switch len(text) {
+	case 2:
+		to, err := ParseSquare(text)
+		if err != nil {
+			return game.Move{}, err
+		}
+		move = game.Move{From: to, To: to, PieceType: game.Goat}
+	case 4:
+		from, err := ParseSquare(text[:2])
+		if err != nil {
+			return game.Move{}, err
+		}
+		to, err := ParseSquare(text[2:])
+		if err != nil {
+			return game.Move{}, err
+		}
+		move = game.Move{From: from, To: to, PieceType: state.CurrentTurn}
+	default:
+		return game.Move{}, ErrSyntax
+	}
+
+	legal, ok := game.FindLegalMove(state, move)
+	if !ok {
+		return game.Move{}, ErrIllegalMove
+	}
+	return legal, nil
+}
+
+// FormatPosition 返回局面的 fen 記法
+func FormatPosition(state *game.GameState) string {
+	var b strings.Builder
+	for y := 0; y < game.BoardSize; y++ {
+		if y > 0 {
+			b.WriteByte('/')
+		}
+		empty := 0
+		for x := 0; x < game.BoardSize; x++ {
+			piece := state.Board[y][x]
+			if piece == game.Empty {
+				empty++
+				continue
+			}
+			if empty > 0 {
+				b.WriteString(strconv.Itoa(empty))
+				empty = 0
+			}
+			if piece == game.Tiger {
+				b.WriteByte('T')
+			} else {
+				b.WriteByte('G')
+			}
+		}
+		if empty > 0 {
+			b.WriteString(strconv.Itoa(empty))
+		}
+	}
+
+	side := "g"
+	if state.CurrentTurn == game.Tiger {
+		side = "t"
+	}
+	fmt.Fprintf(&b, " %s %d %d", side, state.GoatsInHand, state.CapturedGoats)
+	return b.String()
+}
+
+// ParsePosition 解析 fen 記法的四個字段，並重新判定勝負
+func ParsePosition(fields []string) (game.GameState, error) {
+	if len(fields) != 4 {
+		return game.GameState{}, ErrSyntax
+	}
+
+	var state game.GameState
+	rows := strings.Split(fields[0], "/")
+	if len(rows) != game.BoardSize {
+		return game.GameState{}, ErrSyntax
+	}
+	for y, row := range rows {
+		x := 0
+		for _, c := range row {
+			switch {
+			case c >= '1' && c <= '5':
+				x += int(c - '0')
+				continue
+			case x >= game.BoardSize:
+				return game.GameState{}, ErrSyntax
+			case c == 'T':
+				state.Board[y][x] = game.Tiger
+			case c == 'G':
+				state.Board[y][x] = game.Goat
+			default:
+				return game.GameState{}, ErrSyntax
+			}
+			x++
+		}
+		if x != game.BoardSize {
+			return game.GameState{}, ErrSyntax
+		}
+	}
+
+	switch fields[1] {
+	case "t":
+		state.CurrentTurn = game.Tiger
+	case "g":
+		state.CurrentTurn = game.Goat
+	default:
+		return game.GameState{}, ErrSyntax
+	}
+
+	var err error
+	if state.GoatsInHand, err = strconv.Atoi(fields[2]); err != nil || state.GoatsInHand < 0 || state.GoatsInHand > game.MaxGoats {
+		return game.GameState{}, ErrSyntax
+	}
+	if state.CapturedGoats, err = strconv.Atoi(fields[3]); err != nil || state.CapturedGoats < 0 {
+		return game.GameState{}, ErrSyntax
+	}
+
+	state.IsGameOver, state.Winner = game.Outcome(&state)
+	return state, nil
+}
+
+// PositionCommand 返回以開局及移動記錄描述遊戲當前局面的 position 命令
+func PositionCommand(history []game.Move) string {
+	if len(history) == 0 {
+		return "position startpos"
+	}
+	moves := make([]string, len(history))
+	for i, move := range history {
+		moves[i] = FormatMove(move)
+	}
+	return "position startpos moves " + strings.Join(moves, " ")
+}
@@ -0,0 +1,92 @@
+package protocol
+
+import (
+	"errors"
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/nelawu/BagchalGolang/internal/domain/game"
+)
+
+func TestSquareRoundTrip(t *testing.T) {
+	for x := 0; x < game.BoardSize; x++ {
+		for y := 0; y < game.BoardSize; y++ {
+			p := game.Position{X: x, Y: y}
+			got, err := ParseSquare(FormatSquare(p))
+			if err != nil || got != p {
+				t.Errorf("%v: got %v, %v", p, got, err)
+			}
+		}
+	}
+	if got := FormatSquare(game.Position{X: 2, Y: 2}); got != "c3" {
+		t.Errorf("centre = %q, want c3", got)
+	}
+}
+
+func TestStartPosition(t *testing.T) {
+	state := game.NewGameState()
+	if got, want := FormatPosition(&state), "T3T/5/5/5/T3T g 20 0"; got != want {
+		t.Errorf("FormatPosition = %q, want %q", got, want)
+	}
+}
+
+// TestRoundTripRandomGames 在隨機對局的每個局面檢查所有合法移動及局面記法都能還原
+func TestRoundTripRandomGames(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 50; i++ {
+		state := game.NewGameState()
+		for ply := 0; ply < 150 && !state.IsGameOver; ply++ {
+			parsed, err := ParsePosition(strings.Fields(FormatPosition(&state)))
+			if err != nil {
+				t.Fatalf("ParsePosition(%q): %v", FormatPosition(&state), err)
+			}
+			want := state
+			want.LastMove = nil
+			if !reflect.DeepEqual(parsed, want) {
+				t.Fatalf("position %q did not round-trip:\n got %+v\nwant %+v", FormatPosition(&state), parsed, want)
+			}
+
+			moves := game.LegalMoves(&state)
+			for _, move := range moves {
+				got, err := ParseMove(&state, FormatMove(move))
+				if err != nil {
+					t.Fatalf("ParseMove(%q) in %q: %v", FormatMove(move), FormatPosition(&state), err)
+				}
+				if !reflect.DeepEqual(got, move) {
+					t.Fatalf("move %q in %q: got %+v, want %+v", FormatMove(move), FormatPosition(&state), got, move)
+				}
+			}
+			game.ApplyMove(&state, moves[rng.Intn(len(moves))])
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	state := game.NewGameState()
+	for _, text := range []string{"", "c", "f1", "a6", "a1b", "a1b2c3"} {
+		if _, err := ParseMove(&state, text); !errors.Is(err, ErrSyntax) {
+			t.Errorf("ParseMove(%q) = %v, want ErrSyntax", text, err)
+		}
+	}
+	// 開局輪到羊放置，a1 有虎、a1a2 不是羊的移動
+	for _, text := range []string{"a1", "a1a2"} {
+		if _, err := ParseMove(&state, text); !errors.Is(err, ErrIllegalMove) {
+			t.Errorf("ParseMove(%q) = %v, want ErrIllegalMove", text, err)
+		}
+	}
+	for _, fen := range []string{
+		"T3T/5/5/5/T3T g 20",
+		"T3T/5/5/5 g 20 0",
+		"T4T/5/5/5/T3T g 20 0",
+		"T3T/5/5/5/T3X g 20 0",
+		"T3T/5/5/5/T3T x 20 0",
+		"T3T/5/5/5/T3T g 21 0",
+		"T3T/5/5/5/T3T g 20 -1",
+	} {
+		if _, err := ParsePosition(strings.Fields(fen)); !errors.Is(err, ErrSyntax) {
+			t.Errorf("ParsePosition(%q) = %v, want ErrSyntax", fen, err)
+		}
+	}
+}
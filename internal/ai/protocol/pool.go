@@ -0,0 +1,78 @@
+package protocol
+
+import (
+	"errors"
+	"log"
+
+	"github.com/nelawu/BagchalGolang/internal/domain/game"
+)
+
+// Pool 管理同一外部引擎的多個程序，實現 game.AIEngine
+// 每個請求借用一個空閒程序，多局遊戲可同時思考；程序結束或超時後終止，下次借用時重新啟動
+type Pool struct {
+	Name string // 引擎在握手時回報的名稱
+
+	path string
+	args []string
+	idle chan *Client // 空閒的程序，nil 表示需要重新啟動
+}
+
+// NewPool 啟動 size 個外部引擎程序
+func NewPool(size int, path string, args ...string) (*Pool, error) {
+	p := &Pool{
+		Name: path,
+		path: path,
+		args: args,
+		idle: make(chan *Client, max(1, size)),
+	}
+	for i := 0; i < cap(p.idle); i++ {
+		client, err := Start(path, args...)
+		if err != nil {
+			for len(p.idle) > 0 {
+				(<-p.idle).Close()
+			}
+			return nil, err
+		}
+		p.Name = client.Name
+		p.idle <- client
+	}
+	return p, nil
+}
+
+// CalculateNextMove 借用一個程序計算下一步，所有程序都在思考時等待
+// 程序結束、超時或無法寫入時終止它，並以新程序重試一次
+func (p *Pool) CalculateNextMove(g *game.Game) (*game.Move, error) {
+	client := <-p.idle
+	defer func() { p.idle <- client }()
+
+	var err error
+	for attempt := 0; attempt < 2; attempt++ {
+		if client == nil {
+			if client, err = Start(p.path, p.args...); err != nil {
+				client = nil
+				return nil, err
+			}
+		}
+
+		var move *game.Move
+		move, err = client.CalculateNextMove(g)
+		if err == nil || errors.Is(err, ErrIllegalMove) || errors.Is(err, ErrSyntax) {
+			return move, err
+		}
+		log.Printf("外部引擎 %s 失敗，重新啟動: %v", p.Name, err)
+		client.kill()
+		client = nil
+	}
+	return nil, err
+}
+
+// Close 等待所有程序空閒後結束它們
+func (p *Pool) Close() error {
+	var errs []error
+	for i := 0; i < cap(p.idle); i++ {
+		if client := <-p.idle; client != nil {
+			errs = append(errs, client.Close())
+		}
+	}
+	return errors.Join(errs...)
+}
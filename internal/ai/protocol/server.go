@@ -0,0 +1,187 @@
+package protocol
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nelawu/BagchalGolang/internal/ai"
+	"github.com/nelawu/BagchalGolang/internal/domain/game"
+)
+
+// Server 以 bpi 協議提供內建搜索引擎
+type Server struct {
+	name   string
+	engine *ai.Engine
+	table  *ai.TranspositionTable
+	state  game.GameState
+	valid  bool // 最近一次 position 命令是否有效，無效時 go 回應 bestmove none
+	out    *bufio.Writer
+}
+
+func NewServer(name string, engine *ai.Engine) *Server {
+	table := ai.NewTranspositionTable(ai.DefaultTableSizeMB)
+	engine.SetTranspositionTable(table)
+	return &Server{
+		name:   name,
+		engine: engine,
+		table:  table,
+		state:  game.NewGameState(),
+		valid:  true,
+	}
+}
+
+// Serve 逐行處理命令直到 quit 或輸入結束，無法識別的命令按協議慣例忽略
+func (s *Server) Serve(r io.Reader, w io.Writer) error {
+	s.out = bufio.NewWriter(w)
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+
+		switch fields[0] {
+		case "bpi":
+			s.send("id name %s", s.name)
+			s.send("option name Threads type spin default 1 min 1 max 64")
+			s.send("option name Hash type spin default %d min 1 max 4096", ai.DefaultTableSizeMB)
+			s.send("option name Weights type string default <empty>")
+			s.send("bpiok")
+		case "isready":
+			s.send("readyok")
+		case "newgame":
+			s.table.Clear()
+			s.state, s.valid = game.NewGameState(), true
+		case "setoption":
+			s.setOption(fields[1:])
+		case "position":
+			s.position(fields[1:])
+		case "go":
+			s.search(fields[1:])
+		case "quit":
+			return s.out.Flush()
+		}
+		if err := s.out.Flush(); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
+
+// send 寫入一行回應
+func (s *Server) send(format string, args ...any) {
+	fmt.Fprintf(s.out, format+"\n", args...)
+}
+
+// setOption 處理 setoption name <選項> value <值>
+func (s *Server) setOption(fields []string) {
+	if len(fields) < 4 || fields[0] != "name" || fields[2] != "value" {
+		s.send("info string invalid setoption")
+		return
+	}
+	value := strings.Join(fields[3:], " ")
+	switch fields[1] {
+	case "Threads":
+		if n, err := strconv.Atoi(value); err == nil {
+			s.engine.SetThreads(n)
+		}
+	case "Hash":
+		if n, err := strconv.Atoi(value); err == nil && n > 0 {
+			s.table = ai.NewTranspositionTable(n)
+			s.engine.SetTranspositionTable(s.table)
+		}
+	case "Weights":
+		weights, err := ai.LoadWeights(value)
+		if err != nil {
+			s.send("info string cannot load weights: %v", err)
+			return
+		}
+		s.engine.SetWeights(weights)
+		s.table.Clear()
+	default:
+		s.send("info string unknown option %s", fields[1])
+	}
+}
+
+// position 處理 position startpos|fen <局面> [moves <移動>...]
+// 局面或移動無效時不保留之前的局面，之後的 go 回應 bestmove none，以免為錯誤的局面給出移動
+func (s *Server) position(fields []string) {
+	s.valid = false
+	var state game.GameState
+	var rest []string
+	switch {
+	case len(fields) >= 1 && fields[0] == "startpos":
+		state, rest = game.NewGameState(), fields[1:]
+	case len(fields) >= 5 && fields[0] == "fen":
+		var err error
+		if state, err = ParsePosition(fields[1:5]); err != nil {
+			s.send("info string invalid position")
+			return
+		}
+		rest = fields[5:]
+	default:
+		s.send("info string invalid position")
+		return
+	}
+
+	if len(rest) > 0 {
+		if rest[0] != "moves" {
+			s.send("info string invalid position")
+			return
+		}
+		for _, text := range rest[1:] {
+			move, err := ParseMove(&state, text)
+			if err != nil {
+				s.send("info string illegal move %s", text)
+				return
+			}
+			game.ApplyMove(&state, move)
+		}
+	}
+	s.state, s.valid = state, true
+}
+
+// search 處理 go [movetime <毫秒>] [depth <深度>]，未指定時按預設深度搜索
+func (s *Server) search(fields []string) {
+	var limits ai.Limits
+	for i := 0; i+1 < len(fields); i += 2 {
+		n, err := strconv.Atoi(fields[i+1])
+		if err != nil {
+			continue
+		}
+		switch fields[i] {
+		case "movetime":
+			limits.MoveTime = time.Duration(n) * time.Millisecond
+		case "depth":
+			limits.Depth = n
+		}
+	}
+	if limits.MoveTime > 0 && limits.Depth == 0 {
+		limits.Depth = ai.MaxDepth
+	}
+
+	if !s.valid {
+		s.send("bestmove none")
+		return
+	}
+	result := s.engine.Analyze(s.state, limits)
+	if result.BestMove == nil {
+		s.send("bestmove none")
+		return
+	}
+
+	score := result.TigerScore
+	if s.state.CurrentTurn == game.Goat {
+		score = result.GoatScore
+	}
+	pv := make([]string, len(result.PV))
+	for i, move := range result.PV {
+		pv[i] = FormatMove(move)
+	}
+	s.send("info depth %d score %d nodes %d time %d pv %s", result.Depth, score, result.Nodes, result.ElapsedMs, strings.Join(pv, " "))
+	s.send("bestmove %s", FormatMove(*result.BestMove))
+}
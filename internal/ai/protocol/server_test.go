@@ -0,0 +1,57 @@
+package protocol
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/nelawu/BagchalGolang/internal/ai"
+)
+
+// serve 依次發送命令，返回所有 bestmove 行
+func serve(t *testing.T, commands ...string) []string {
+	t.Helper()
+	var out bytes.Buffer
+	server := NewServer("test", ai.NewEngine(1))
+	if err := server.Serve(strings.NewReader(strings.Join(commands, "\n")+"\n"), &out); err != nil {
+		t.Fatal(err)
+	}
+	var bestmoves []string
+	for _, line := range strings.Split(out.String(), "\n") {
+		if strings.HasPrefix(line, "bestmove ") {
+			bestmoves = append(bestmoves, line)
+		}
+	}
+	return bestmoves
+}
+
+func TestInvalidPositionClearsState(t *testing.T) {
+	tests := []struct {
+		name     string
+		position string
+	}{
+		{"invalid fen", "position fen T3T/5/5/5 g 20 0"},
+		{"illegal move", "position startpos moves c3 a1a3"},
+		{"unparsable move", "position startpos moves zz"},
+		{"missing moves keyword", "position startpos c3"},
+		{"unknown form", "position middlegame"},
+	}
+	for _, tt := range tests {
+		// 先設置有效局面，無效的 position 不可沿用它
+		got := serve(t, "position startpos moves c3", tt.position, "go depth 1")
+		if len(got) != 1 || got[0] != "bestmove none" {
+			t.Errorf("%s: got %q, want bestmove none", tt.name, got)
+		}
+	}
+
+	// 之後的有效局面及 newgame 恢復搜索
+	for _, commands := range [][]string{
+		{"position middlegame", "position startpos moves c3", "go depth 1"},
+		{"position middlegame", "newgame", "go depth 1"},
+	} {
+		got := serve(t, commands...)
+		if len(got) != 1 || got[0] == "bestmove none" {
+			t.Errorf("%q: got %q, want a move", commands, got)
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package ai
+
+import (
+	"errors"
+	"sort"
+	"sync"
+
+	"github.com/nelawu/BagchalGolang/internal/domain/game"
+)
+
+var ErrUnknownEngine = errors.New("unknown engine")
+
+// 內建引擎的名稱，分別對應難度 1-3 的 Engine
+const (
+	EngineRandom    = "random"
+	EngineGreedy    = "greedy"
+	EngineAlphaBeta = "alphabeta"
+)
+
+// LevelEngines 各難度預設使用的內建引擎
+var LevelEngines = map[int]string{
+	1: EngineRandom,
+	2: EngineGreedy,
+	3: EngineAlphaBeta,
+}
+
+// Registry 按名稱登記引擎，並將AI難度對應到引擎，實現 game.AIEngineSelector
+type Registry struct {
+	mu           sync.RWMutex
+	engines      map[string]game.AIEngine
+	levels       map[int]string
+	defaultLevel int // 未對應引擎的難度使用此難度的引擎
+}
+
+func NewRegistry(defaultLevel int) *Registry {
+	return &Registry{
+		engines:      make(map[string]game.AIEngine),
+		levels:       make(map[int]string),
+		defaultLevel: defaultLevel,
+	}
+}
+
+// Register 以名稱登記引擎，同名的引擎會被替換
+func (r *Registry) Register(name string, engine game.AIEngine) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.engines[name] = engine
+}
+
+// Assign 指定某個難度使用的引擎，引擎需已登記
+func (r *Registry) Assign(level int, name string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.engines[name]; !ok {
+		return ErrUnknownEngine
+	}
+	r.levels[level] = name
+	return nil
+}
+
+// Get 按名稱獲取引擎
+func (r *Registry) Get(name string) (game.AIEngine, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	engine, ok := r.engines[name]
+	return engine, ok
+}
+
+// EngineForLevel 返回難度對應的引擎，未對應時使用預設難度的引擎，都沒有時返回 nil
+func (r *Registry) EngineForLevel(level int) game.AIEngine {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	name, ok := r.levels[level]
+	if !ok {
+		name = r.levels[r.defaultLevel]
+	}
+	return r.engines[name]
+}
+
+// Names 返回所有已登記引擎的名稱
+func (r *Registry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	names := make([]string, 0, len(r.engines))
+	for name := range r.engines {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Levels 返回各難度對應的引擎名稱
+func (r *Registry) Levels() map[int]string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	levels := make(map[int]string, len(r.levels))
+	for level, name := range r.levels {
+		levels[level] = name
+	}
+	return levels
+}
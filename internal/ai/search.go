@@ -0,0 +1,324 @@
+package ai
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/nelawu/BagchalGolang/internal/ai/tablebase"
+	"github.com/nelawu/BagchalGolang/internal/domain/game"
+)
+
+// 搜索參數
+const (
+	// WinScore 勝負已分時的分數，減去到達終局的步數使搜索偏好更快的勝利
+	WinScore = 100000
+
+	// DefaultDepth 未指定深度時的搜索深度
+	DefaultDepth = 4
+
+	// MaxDepth 允許的最大搜索深度
+	MaxDepth = 8
+
+	// nodeCheckInterval 每搜索多少個節點檢查一次時間
+	nodeCheckInterval = 1024
+
+	// maxTablebasePV 殘局庫主要變例的最大長度
+	maxTablebasePV = 40
+)
+
+// Limits 搜索限制，Depth 與 MoveTime 任一達到即停止
+type Limits struct {
+	Depth    int           // 最大深度，0 表示 DefaultDepth
+	MoveTime time.Duration // 時間預算，0 表示不限時
+}
+
+// Analysis 局面分析結果
+type Analysis struct {
+	TigerScore int         `json:"tigerScore"`         // 虎方視角的評估
+	GoatScore  int         `json:"goatScore"`          // 羊方視角的評估
+	BestMove   *game.Move  `json:"bestMove,omitempty"` // 最佳移動，終局時為空
+	PV         []game.Move `json:"pv"`                 // 主要變例
+	Depth      int         `json:"depth"`              // 完成的搜索深度
+	Nodes      int64       `json:"nodes"`              // 搜索的節點數
+	ElapsedMs  int64       `json:"elapsedMs"`
+
+	// Tablebase 局面在殘局庫中時的完美對弈結果
+	Tablebase *TablebaseResult `json:"tablebase,omitempty"`
+}
+
+// TablebaseResult 殘局庫查詢結果，以輪到的一方視角表示
+type TablebaseResult struct {
+	Result   string `json:"result"`   // win、loss 或 draw
+	Distance int    `json:"distance"` // 到達結果的步數
+}
+
+// searcher 保存單次搜索的狀態
+type searcher struct {
+	tablebase *tablebase.Tablebase
+	table     *TranspositionTable // 可為空
+	weights   Weights
+	deadline  time.Time
+	nodes     int64
+	stopped   bool
+	timed     bool         // 是否檢查時間，第一層不檢查以保證總有結果
+	abort     *atomic.Bool // 輔助線程的中止信號，主線程為空
+
+	// 移動排序的啟發資訊，在逐層加深的各輪之間保留
+	rootMove *game.Move // 上一輪的最佳移動，在根節點優先搜索
+	killers  [MaxDepth + 1][2]game.Move
+	history  [3][squareCount][squareCount]int // [陣營][起點][終點]
+}
+
+// Analyze 搜索局面並返回評估、最佳移動與主要變例
+// 逐層加深搜索，時間用完時返回最後一個完成深度的結果，第一層總是完成
+// 設置了多個線程及置換表時以 lazy SMP 並行搜索：輔助線程以錯開的深度搜索同一局面，
+// 通過共用的置換表為主線程提供剪枝資訊，結果以主線程為準
+func (e *Engine) Analyze(state game.GameState, limits Limits) *Analysis {
+	start := time.Now()
+	if result := e.probeTablebase(state); result != nil {
+		result.ElapsedMs = time.Since(start).Milliseconds()
+		return result
+	}
+
+	depth := limits.Depth
+	if depth <= 0 {
+		depth = DefaultDepth
+	}
+	if depth > MaxDepth {
+		depth = MaxDepth
+	}
+	if e.table != nil {
+		e.table.newSearch()
+	}
+
+	primary := e.newSearcher(start, limits)
+	if e.threads <= 1 || e.table == nil {
+		return primary.deepen(state, depth, start, limits)
+	}
+
+	var abort atomic.Bool
+	var wg sync.WaitGroup
+	helpers := make([]*searcher, e.threads-1)
+	for i := range helpers {
+		helper := e.newSearcher(start, limits)
+		helper.abort = &abort
+		helpers[i] = helper
+		wg.Add(1)
+		go func(startDepth int) {
+			defer wg.Done()
+			helper.iterate(state, startDepth, depth)
+		}(1 + (i+1)%2)
+	}
+
+	result := primary.deepen(state, depth, start, limits)
+	abort.Store(true)
+	wg.Wait()
+	for _, helper := range helpers {
+		result.Nodes += helper.nodes
+	}
+	return result
+}
+
+// newSearcher 創建一個搜索線程的狀態
+func (e *Engine) newSearcher(start time.Time, limits Limits) *searcher {
+	s := &searcher{tablebase: e.tablebase, table: e.table, weights: e.weights}
+	if limits.MoveTime > 0 {
+		s.deadline = start.Add(limits.MoveTime)
+	}
+	return s
+}
+
+// deepen 主線程的逐層加深搜索
+func (s *searcher) deepen(state game.GameState, depth int, start time.Time, limits Limits) *Analysis {
+	result := &Analysis{PV: []game.Move{}}
+	for d := 1; d <= depth; d++ {
+		s.rootMove = result.BestMove
+		s.timed = d > 1
+		score, pv := s.alphaBeta(&state, d, 0, -WinScore-1, WinScore+1)
+		// 第一層總是完成，之後被中斷的結果不可信
+		if s.stopped {
+			break
+		}
+
+		result.Depth = d
+		result.PV = append([]game.Move{}, pv...)
+		if len(pv) > 0 {
+			result.BestMove = &result.PV[0]
+		}
+		result.TigerScore = score
+		if state.CurrentTurn == game.Goat {
+			result.TigerScore = -score
+		}
+		if len(pv) == 0 || s.stopped {
+			break
+		}
+		// 下一層的耗時通常是本層的數倍，剩餘時間不足一半時不再開始
+		if !s.deadline.IsZero() && time.Since(start) > limits.MoveTime/2 {
+			break
+		}
+	}
+
+	result.GoatScore = -result.TigerScore
+	result.Nodes = s.nodes
+	result.ElapsedMs = time.Since(start).Milliseconds()
+	return result
+}
+
+// iterate 輔助線程的逐層加深搜索，結果只寫入置換表，直到完成最大深度或被主線程中止
+func (s *searcher) iterate(state game.GameState, startDepth, depth int) {
+	s.timed = true
+	for d := startDepth; d <= depth && !s.stopped; d++ {
+		s.alphaBeta(&state, d, 0, -WinScore-1, WinScore+1)
+	}
+}
+
+// alphaBeta 以輪到的一方視角返回局面分數及主要變例（negamax 形式的 alpha-beta 剪枝）
+func (s *searcher) alphaBeta(state *game.GameState, depth, ply, alpha, beta int) (int, []game.Move) {
+	s.nodes++
+	if s.timed && !s.deadline.IsZero() && s.nodes%nodeCheckInterval == 0 && time.Now().After(s.deadline) {
+		s.stopped = true
+	}
+	if s.abort != nil && s.abort.Load() {
+		s.stopped = true
+	}
+
+	if state.IsGameOver {
+		return terminalScore(state, ply), nil
+	}
+	if s.tablebase != nil && ply > 0 {
+		if result, distance, ok := s.tablebase.Probe(state); ok {
+			return tablebaseScore(result, ply+distance), nil
+		}
+	}
+	if depth == 0 || s.stopped {
+		return s.sideScore(state), nil
+	}
+
+	// 查詢置換表：深度足夠時直接使用之前的結果，否則只取其最佳移動優先搜索
+	var key uint64
+	var sym game.Symmetry
+	var hashMove *game.Move
+	if s.table != nil {
+		key, sym = canonicalHash(state)
+		if score, entryDepth, bound, move, ok := s.table.probe(key, sym, ply); ok {
+			hashMove = move
+			if ply > 0 && entryDepth >= depth {
+				switch {
+				case bound == BoundExact,
+					bound == BoundLower && score >= beta,
+					bound == BoundUpper && score <= alpha:
+					return score, nil
+				}
+			}
+		}
+	}
+
+	moves := game.LegalMoves(state)
+	if len(moves) == 0 {
+		return -WinScore + ply, nil
+	}
+	s.orderMoves(moves, state.CurrentTurn, ply, hashMove)
+
+	originalAlpha := alpha
+	best := -WinScore - 1
+	var bestPV []game.Move
+	for _, move := range moves {
+		child := *state
+		game.ApplyMove(&child, move)
+		score, pv := s.alphaBeta(&child, depth-1, ply+1, -beta, -alpha)
+		score = -score
+		if s.stopped {
+			break
+		}
+		if score > best {
+			best = score
+			bestPV = append([]game.Move{move}, pv...)
+		}
+		if score > alpha {
+			alpha = score
+		}
+		if alpha >= beta {
+			s.recordCutoff(move, state.CurrentTurn, depth, ply)
+			break
+		}
+	}
+	if bestPV == nil {
+		// 搜索被中斷，結果不會被採用
+		return best, nil
+	}
+
+	if s.table != nil && !s.stopped {
+		bound := BoundExact
+		switch {
+		case best <= originalAlpha:
+			bound = BoundUpper
+		case best >= beta:
+			bound = BoundLower
+		}
+		s.table.store(key, sym, ply, depth, best, bound, &bestPV[0])
+	}
+	return best, bestPV
+}
+
+// probeTablebase 局面在殘局庫中時直接返回完美對弈的結果及主要變例
+func (e *Engine) probeTablebase(state game.GameState) *Analysis {
+	if e.tablebase == nil {
+		return nil
+	}
+	move, result, distance, ok := e.tablebase.BestMove(&state)
+	if !ok {
+		return nil
+	}
+
+	analysis := &Analysis{
+		TigerScore: tablebaseScore(result, distance),
+		BestMove:   &move,
+		PV:         []game.Move{},
+		Tablebase:  &TablebaseResult{Result: result.String(), Distance: distance},
+	}
+	if state.CurrentTurn == game.Goat {
+		analysis.TigerScore = -analysis.TigerScore
+	}
+	analysis.GoatScore = -analysis.TigerScore
+
+	// 沿殘局庫的最佳移動展開主要變例
+	for len(analysis.PV) < maxTablebasePV && ok {
+		analysis.PV = append(analysis.PV, move)
+		game.ApplyMove(&state, move)
+		move, _, _, ok = e.tablebase.BestMove(&state)
+	}
+	return analysis
+}
+
+// tablebaseScore 將殘局庫結果轉換為輪到的一方視角的分數，distance 為到達結果的總步數
+func tablebaseScore(result tablebase.Result, distance int) int {
+	switch result {
+	case tablebase.Win:
+		return WinScore - distance
+	case tablebase.Loss:
+		return -WinScore + distance
+	}
+	return 0
+}
+
+// terminalScore 返回終局對輪到的一方的分數
+func terminalScore(state *game.GameState, ply int) int {
+	switch state.Winner {
+	case game.Empty:
+		return 0
+	case state.CurrentTurn:
+		return WinScore - ply
+	default:
+		return -WinScore + ply
+	}
+}
+
+// sideScore 將虎方視角的靜態評估轉換為輪到的一方視角
+func (s *searcher) sideScore(state *game.GameState) int {
+	score := s.weights.Evaluate(state)
+	if state.CurrentTurn == game.Goat {
+		return -score
+	}
+	return score
+}
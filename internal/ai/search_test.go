@@ -0,0 +1,64 @@
+package ai
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"github.com/nelawu/BagchalGolang/internal/domain/game"
+)
+
+// testPositions 返回隨機對局中放置及移動階段的局面
+func testPositions(t *testing.T) []game.GameState {
+	t.Helper()
+	rng := rand.New(rand.NewSource(7))
+	var positions []game.GameState
+	for len(positions) < 6 {
+		state := game.NewGameState()
+		for ply := 0; ply < 60 && !state.IsGameOver; ply++ {
+			if ply == 10 || ply == 45 {
+				positions = append(positions, state)
+			}
+			moves := game.LegalMoves(&state)
+			game.ApplyMove(&state, moves[rng.Intn(len(moves))])
+		}
+	}
+	return positions
+}
+
+func TestSingleThreadSearchIsDeterministic(t *testing.T) {
+	for _, state := range testPositions(t) {
+		var results [2]*Analysis
+		for i := range results {
+			engine := NewEngine(3)
+			engine.SetThreads(1)
+			engine.SetTranspositionTable(NewTranspositionTable(1))
+			results[i] = engine.Analyze(state, Limits{Depth: 4})
+		}
+		first, second := results[0], results[1]
+		if first.TigerScore != second.TigerScore || first.Depth != second.Depth || first.Nodes != second.Nodes {
+			t.Errorf("score/depth/nodes differ: %+v vs %+v", first, second)
+		}
+		if !reflect.DeepEqual(first.PV, second.PV) {
+			t.Errorf("PV differs: %v vs %v", first.PV, second.PV)
+		}
+	}
+}
+
+func TestLazySMPReturnsLegalMove(t *testing.T) {
+	engine := NewEngine(3)
+	engine.SetThreads(4)
+	engine.SetTranspositionTable(NewTranspositionTable(1))
+	for _, state := range testPositions(t) {
+		result := engine.Analyze(state, Limits{Depth: 4})
+		if result.BestMove == nil {
+			t.Fatalf("no best move in a position with legal moves")
+		}
+		if _, ok := game.FindLegalMove(&state, *result.BestMove); !ok {
+			t.Errorf("illegal best move %+v", *result.BestMove)
+		}
+		if result.Depth != 4 {
+			t.Errorf("depth = %d, want 4", result.Depth)
+		}
+	}
+}
@@ -0,0 +1,237 @@
+package tablebase
+
+import (
+	"github.com/nelawu/BagchalGolang/internal/domain/game"
+)
+
+// maxCaptured 移動階段中虎仍未獲勝時最多被吃的羊數
+const maxCaptured = game.CapturesToWin - 1
+
+// neighbors[sq] 為與 sq 以線相連的點；jumps[sq] 為從 sq 沿直線跳躍的 (被跳過的點, 落點)
+var (
+	neighbors [squares][]int
+	jumps     [squares][][2]int
+)
+
+func init() {
+	for y := 0; y < game.BoardSize; y++ {
+		for x := 0; x < game.BoardSize; x++ {
+			sq := y*game.BoardSize + x
+			for _, d := range lineDirections(x, y) {
+				nx, ny := x+d[0], y+d[1]
+				if !inBounds(nx, ny) {
+					continue
+				}
+				neighbors[sq] = append(neighbors[sq], ny*game.BoardSize+nx)
+				jx, jy := x+2*d[0], y+2*d[1]
+				if inBounds(jx, jy) {
+					jumps[sq] = append(jumps[sq], [2]int{ny*game.BoardSize + nx, jy*game.BoardSize + jx})
+				}
+			}
+		}
+	}
+}
+
+// lineDirections 返回某點可走的方向，與 game.LegalMoves 的規則一致
+func lineDirections(x, y int) [][2]int {
+	directions := [][2]int{{0, -1}, {-1, 0}, {1, 0}, {0, 1}}
+	if (x+y)%2 == 0 {
+		directions = append(directions, [2]int{-1, -1}, [2]int{1, -1}, [2]int{-1, 1}, [2]int{1, 1})
+	}
+	return directions
+}
+
+func inBounds(x, y int) bool {
+	return x >= 0 && x < game.BoardSize && y >= 0 && y < game.BoardSize
+}
+
+// Generate 以逆向分析求解被吃 c 隻羊的表
+// next 為被吃 c+1 隻羊的表；c 為 4 時吃子即勝，不需要 next
+// next 缺失或不完整時，經由吃子到達的局面視為未知，表中只有已證明的勝負
+//
+// 局面確定時即把結果寫入表，再按步數分桶傳播，以得到最短的勝利與最長的失敗；
+// 桶中只存 4 字節的索引，每個局面最多排入兩次（吃子得到的勝利被更快的勝利取代時）。
+// 記憶體為每個局面 3 字節（表 2 字節、remaining 1 字節）加上待傳播局面每個 4 字節
+func Generate(captured int, next *Table) *Table {
+	count := positionCount(captured)
+	table := &Table{
+		Captured: captured,
+		Complete: captured == maxCaptured || (next != nil && next.Complete),
+		entries:  make([]uint16, count),
+	}
+	// 尚未證明對對方有利的後續局面數；未解出的條目的步數暫存只能吃子走入對方勝局時，對方最長的勝利步數
+	remaining := make([]uint8, count)
+	var queue bucketQueue
+
+	push := func(idx int64, result Result, distance int) {
+		table.set(idx, result, distance)
+		queue.push(distance, uint32(idx))
+	}
+
+	// 初始化：統計每個局面的後續局面，並處理無子可動及吃子
+	for idx := int64(0); idx < count; idx++ {
+		p := decode(captured, idx)
+		moves, bestWin, worstLoss := 0, -1, -1
+		for sq, piece := range p.board {
+			if piece != p.turn {
+				continue
+			}
+			for _, to := range neighbors[sq] {
+				if p.board[to] == game.Empty {
+					moves++
+				}
+			}
+			if piece != game.Tiger {
+				continue
+			}
+			for _, jump := range jumps[sq] {
+				over, to := jump[0], jump[1]
+				if p.board[over] != game.Goat || p.board[to] != game.Empty {
+					continue
+				}
+				result, distance := captureResult(&p, sq, over, to, next)
+				switch result {
+				case Loss: // 吃子後羊方必敗
+					if bestWin < 0 || distance+1 < bestWin {
+						bestWin = distance + 1
+					}
+				case Win: // 吃子後羊方必勝，此移動不必考慮
+					worstLoss = max(worstLoss, distance+1)
+				default: // 未知或和棋，阻止判負
+					moves++
+				}
+			}
+		}
+
+		switch {
+		case bestWin >= 0:
+			push(idx, Win, bestWin)
+		case moves == 0 && worstLoss >= 0:
+			push(idx, Loss, worstLoss)
+		case moves == 0:
+			push(idx, Loss, 0) // 無子可動
+		default:
+			remaining[idx] = uint8(min(moves, 255))
+			if worstLoss >= 0 {
+				table.set(idx, Unknown, worstLoss)
+			}
+		}
+	}
+
+	// 逆向傳播：從已確定的局面推出前一步的局面
+	for distance := 0; distance < len(queue.buckets); distance++ {
+		// 傳播只會排入更大的步數，處理中的桶不會再增長
+		queue.drain(distance, func(idx int64) {
+			result, d := table.lookup(idx)
+			if d != distance {
+				return // 已以更快的勝利重新排入
+			}
+
+			p := decode(captured, idx)
+			forEachPredecessor(&p, func(prev int64) {
+				prevResult, prevDistance := table.lookup(prev)
+				if result == Loss {
+					// 步數更大的勝利只可能是尚未傳播的吃子勝利
+					if prevResult == Unknown || (prevResult == Win && prevDistance > distance+1) {
+						push(prev, Win, distance+1)
+					}
+					return
+				}
+				if prevResult != Unknown || remaining[prev] == 0 {
+					return
+				}
+				remaining[prev]--
+				if remaining[prev] == 0 {
+					push(prev, Loss, max(distance+1, prevDistance))
+				}
+			})
+		})
+	}
+
+	// 剩餘的局面雙方都無法強制取勝，表不完整時則為未知
+	for idx := int64(0); idx < count; idx++ {
+		if table.resolved(idx) {
+			continue
+		}
+		if table.Complete {
+			table.set(idx, Draw, 0)
+		} else {
+			table.set(idx, Unknown, 0)
+		}
+	}
+	return table
+}
+
+// chunkSize 桶中每塊的索引數
+const chunkSize = 1 << 20
+
+// bucketQueue 按步數分桶的待傳播局面索引，buckets[d] 為結果在 d 步確定的局面
+// 桶以固定大小的塊存儲，處理完的塊重複使用，避免切片擴容時複製及留下大量待回收的記憶體
+type bucketQueue struct {
+	buckets [][][]uint32
+	free    [][]uint32
+}
+
+// push 將局面索引排入步數 distance 的桶
+func (q *bucketQueue) push(distance int, idx uint32) {
+	for len(q.buckets) <= distance {
+		q.buckets = append(q.buckets, nil)
+	}
+	chunks := q.buckets[distance]
+	if n := len(chunks); n == 0 || len(chunks[n-1]) == chunkSize {
+		var chunk []uint32
+		if k := len(q.free); k > 0 {
+			chunk, q.free = q.free[k-1][:0], q.free[:k-1]
+		} else {
+			chunk = make([]uint32, 0, chunkSize)
+		}
+		chunks = append(chunks, chunk)
+	}
+	chunks[len(chunks)-1] = append(chunks[len(chunks)-1], idx)
+	q.buckets[distance] = chunks
+}
+
+// drain 依次處理步數 distance 的桶並回收其記憶體，visit 不可向同一個桶排入
+func (q *bucketQueue) drain(distance int, visit func(idx int64)) {
+	for _, chunk := range q.buckets[distance] {
+		for _, idx := range chunk {
+			visit(int64(idx))
+		}
+		q.free = append(q.free, chunk)
+	}
+	q.buckets[distance] = nil
+}
+
+// captureResult 返回虎吃子後羊方的結果及步數
+func captureResult(p *position, from, over, to int, next *Table) (Result, int) {
+	if p.captured+1 >= game.CapturesToWin {
+		return Loss, 0
+	}
+	if next == nil {
+		return Unknown, 0
+	}
+	child := *p
+	child.board[from], child.board[over], child.board[to] = game.Empty, game.Empty, game.Tiger
+	child.captured++
+	child.turn = game.Goat
+	return next.lookup(child.index())
+}
+
+// forEachPredecessor 列舉經由一步不吃子的移動到達此局面的前一局面
+func forEachPredecessor(p *position, visit func(int64)) {
+	mover := game.Opponent(p.turn)
+	for sq, piece := range p.board {
+		if piece != mover {
+			continue
+		}
+		for _, from := range neighbors[sq] {
+			if p.board[from] != game.Empty {
+				continue
+			}
+			prev := *p
+			prev.board[from], prev.board[sq] = mover, game.Empty
+			prev.turn = mover
+			visit(prev.index())
+		}
+	}
+}
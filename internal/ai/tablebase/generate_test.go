@@ -0,0 +1,146 @@
+package tablebase
+
+import (
+	"testing"
+
+	"github.com/nelawu/BagchalGolang/internal/domain/game"
+)
+
+// filledTable 返回所有條目均為同一結果的表，用作 Generate 的更高層表
+func filledTable(captured int, result Result, distance int) *Table {
+	t := &Table{Captured: captured, Complete: true, entries: make([]uint16, positionCount(captured))}
+	for idx := range t.entries {
+		t.set(int64(idx), result, distance)
+	}
+	return t
+}
+
+// boardState 由棋盤圖建立移動階段的局面，第 y 行第 x 個字元為 (x, y)：T 虎、G 羊、. 空
+func boardState(turn game.PieceType, captured int, rows ...string) game.GameState {
+	state := game.GameState{CurrentTurn: turn, CapturedGoats: captured}
+	for y, row := range rows {
+		for x, c := range row {
+			switch c {
+			case 'T':
+				state.Board[y][x] = game.Tiger
+			case 'G':
+				state.Board[y][x] = game.Goat
+			}
+		}
+	}
+	return state
+}
+
+func TestGenerateKnownResults(t *testing.T) {
+	// 四隻虎困在角落，唯一的空位不在任何虎的跳躍落點上
+	trapped := []string{"TGGGT", "GG.GG", "GGGGG", "GGGGG", "TGGGT"}
+	// 虎只能吃子：(0,0) 及 (4,0) 的虎都可跳到 (2,0)
+	captureOnly := []string{"TG.GT", "GGGGG", "GGGGG", "GGGGG", "TGGGT"}
+
+	tests := []struct {
+		name     string
+		next     *Table
+		state    game.GameState
+		result   Result
+		distance int
+		known    bool
+	}{
+		{"trapped tigers lose at once", nil, boardState(game.Tiger, 0, trapped...), Loss, 0, true},
+		{"capture into an unknown table", nil, boardState(game.Tiger, 0, captureOnly...), Unknown, 0, false},
+		{"capture into a lost goat position", filledTable(1, Loss, 0), boardState(game.Tiger, 0, captureOnly...), Win, 1, true},
+		{"capture distance adds one", filledTable(1, Loss, 3), boardState(game.Tiger, 0, captureOnly...), Win, 4, true},
+		{"only captures into goat wins", filledTable(1, Win, 2), boardState(game.Tiger, 0, captureOnly...), Loss, 3, true},
+	}
+	for _, tt := range tests {
+		tb := New(Generate(0, tt.next))
+		result, distance, known := tb.Probe(&tt.state)
+		if result != tt.result || distance != tt.distance || known != tt.known {
+			t.Errorf("%s: Probe = %v %d %v, want %v %d %v", tt.name, result, distance, known, tt.result, tt.distance, tt.known)
+		}
+	}
+}
+
+func TestFifthCaptureWins(t *testing.T) {
+	// 被吃 4 隻羊的表過大，不在測試中生成；吃第 5 隻羊的結果不查表，直接為羊方即時落敗
+	state := boardState(game.Tiger, maxCaptured, "TG..T", "GG...", "GGGGG", "GGGGG", "TGGGT")
+	p, ok := fromState(&state)
+	if !ok {
+		t.Fatal("position not in the movement phase")
+	}
+	if result, distance := captureResult(&p, 0, 1, 2, nil); result != Loss || distance != 0 {
+		t.Errorf("captureResult = %v %d, want loss 0", result, distance)
+	}
+
+	// 與 Generate 初始化的計算一致：吃子使羊方 0 步落敗，虎方 1 步獲勝
+	child := state
+	move, ok := game.FindLegalMove(&child, game.Move{From: game.Position{X: 0, Y: 0}, To: game.Position{X: 2, Y: 0}, PieceType: game.Tiger})
+	if !ok {
+		t.Fatal("capture not legal")
+	}
+	game.ApplyMove(&child, move)
+	if !child.IsGameOver || child.Winner != game.Tiger {
+		t.Errorf("5th capture did not end the game")
+	}
+}
+
+func TestGenerateIsConsistent(t *testing.T) {
+	// 吃子後的局面全為羊方 0 步或 3 步落敗、或 2 步獲勝；3 步落敗時吃子的勝利會被更快的勝利取代
+	for _, next := range []struct {
+		result   Result
+		distance int
+	}{{Loss, 0}, {Loss, 3}, {Win, 2}} {
+		checkConsistent(t, Generate(0, filledTable(1, next.result, next.distance)), next.result, next.distance)
+	}
+}
+
+// checkConsistent 抽樣檢查表中的勝負與步數符合後續局面的結果
+func checkConsistent(t *testing.T, table *Table, nextResult Result, nextDistance int) {
+	t.Helper()
+	tb := New(table)
+	for idx := int64(0); idx < int64(len(table.entries)); idx += 97 {
+		result, distance := table.lookup(idx)
+		if result != Win && result != Loss {
+			continue
+		}
+		p := decode(0, idx)
+		state := p.toState()
+
+		// 勝局至少有一步走入對方步數少一的敗局；敗局的所有移動都走入對方的勝局
+		best := -1
+		for _, move := range game.LegalMoves(&state) {
+			child := state
+			game.ApplyMove(&child, move)
+			var childResult Result
+			var childDistance int
+			switch {
+			case move.Capture != nil:
+				childResult, childDistance = nextResult, nextDistance
+			case child.IsGameOver: // 走完即結束的移動使對方落敗，與 BestMove 相同
+				childResult, childDistance = Loss, 0
+			default:
+				var known bool
+				if childResult, childDistance, known = tb.Probe(&child); !known {
+					t.Fatalf("idx %d: resolved position has an unknown child", idx)
+				}
+			}
+
+			switch result {
+			case Win:
+				if childResult == Loss && (best < 0 || childDistance < best) {
+					best = childDistance
+				}
+			case Loss:
+				if childResult != Win {
+					t.Fatalf("idx %d: lost position has a %v child", idx, childResult)
+				}
+				best = max(best, childDistance)
+			}
+		}
+		if result == Win && best+1 != distance {
+			t.Fatalf("idx %d: win in %d but fastest child loss is %d", idx, distance, best)
+		}
+		if result == Loss && distance > 0 && best+1 != distance {
+			t.Fatalf("idx %d: loss in %d but slowest child win is %d", idx, distance, best)
+		}
+	}
+}
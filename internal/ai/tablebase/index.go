@@ -0,0 +1,163 @@
+package tablebase
+
+import "github.com/nelawu/BagchalGolang/internal/domain/game"
+
+// 移動階段的局面：4 隻虎，20-c 隻羊在棋盤上，1+c 個空位（c 為被吃的羊數）
+// 局面索引 = ((虎位置組合序號 * 空位組合數) + 空位組合序號) * 2 + 輪到的一方
+// 空位的組合序號以去掉虎之後剩下的 21 個點為基準
+
+const (
+	squares       = game.BoardSize * game.BoardSize
+	nonTigerCount = squares - game.MaxTigers
+)
+
+// binomial[n][k] = C(n, k)
+var binomial [squares + 1][squares + 1]int64
+
+func init() {
+	for n := 0; n <= squares; n++ {
+		binomial[n][0] = 1
+		for k := 1; k <= n; k++ {
+			binomial[n][k] = binomial[n-1][k-1] + binomial[n-1][k]
+		}
+	}
+}
+
+// maxEmpties 移動階段棋盤上最多的空位數，見 emptyCount
+const maxEmpties = game.CapturesToWin
+
+// emptyCount 返回被吃 c 隻羊時棋盤上的空位數
+func emptyCount(captured int) int {
+	return 1 + captured
+}
+
+// positionCount 返回被吃 c 隻羊時的局面總數（含雙方輪走）
+func positionCount(captured int) int64 {
+	return binomial[squares][game.MaxTigers] * binomial[nonTigerCount][emptyCount(captured)] * 2
+}
+
+// rankCombination 返回遞增序列在組合中的序號（組合數系統）
+func rankCombination(items []int) int64 {
+	var rank int64
+	for i, item := range items {
+		rank += binomial[item][i+1]
+	}
+	return rank
+}
+
+// unrankCombination 將序號還原為長度為 k 的遞增序列
+func unrankCombination(rank int64, k int, items []int) {
+	for i := k; i >= 1; i-- {
+		item := i - 1
+		for binomial[item+1][i] <= rank {
+			item++
+		}
+		items[i-1] = item
+		rank -= binomial[item][i]
+	}
+}
+
+// position 移動階段局面的緊湊表示
+type position struct {
+	board    [squares]game.PieceType
+	captured int
+	turn     game.PieceType
+}
+
+// index 返回局面在表中的索引
+func (p *position) index() int64 {
+	// 以定長陣列存放組合，避免生成表時每個局面都分配記憶體
+	var tigers [game.MaxTigers]int
+	var emptyBuf [maxEmpties]int
+	empties := emptyBuf[:0]
+	t, rest := 0, 0
+	for sq := 0; sq < squares; sq++ {
+		switch p.board[sq] {
+		case game.Tiger:
+			tigers[t] = sq
+			t++
+			continue
+		case game.Empty:
+			empties = append(empties, rest)
+		}
+		rest++
+	}
+
+	idx := rankCombination(tigers[:])*binomial[nonTigerCount][len(empties)] + rankCombination(empties)
+	idx *= 2
+	if p.turn == game.Goat {
+		idx++
+	}
+	return idx
+}
+
+// decode 從索引還原局面
+func decode(captured int, idx int64) position {
+	p := position{captured: captured, turn: game.Tiger}
+	if idx%2 == 1 {
+		p.turn = game.Goat
+	}
+	idx /= 2
+
+	emptyCombos := binomial[nonTigerCount][emptyCount(captured)]
+	var tigers [game.MaxTigers]int
+	unrankCombination(idx/emptyCombos, game.MaxTigers, tigers[:])
+	var emptyBuf [maxEmpties]int
+	empties := emptyBuf[:emptyCount(captured)]
+	unrankCombination(idx%emptyCombos, len(empties), empties)
+
+	for _, sq := range tigers {
+		p.board[sq] = game.Tiger
+	}
+	rest, e := 0, 0
+	for sq := 0; sq < squares; sq++ {
+		if p.board[sq] == game.Tiger {
+			continue
+		}
+		if e < len(empties) && empties[e] == rest {
+			p.board[sq] = game.Empty
+			e++
+		} else {
+			p.board[sq] = game.Goat
+		}
+		rest++
+	}
+	return p
+}
+
+// fromState 將遊戲狀態轉換為緊湊局面，非移動階段返回 false
+func fromState(state *game.GameState) (position, bool) {
+	if state.GoatsInHand != 0 || state.CapturedGoats >= game.CapturesToWin {
+		return position{}, false
+	}
+	p := position{captured: state.CapturedGoats, turn: state.CurrentTurn}
+	tigers, goats := 0, 0
+	for y := 0; y < game.BoardSize; y++ {
+		for x := 0; x < game.BoardSize; x++ {
+			piece := state.Board[y][x]
+			p.board[y*game.BoardSize+x] = piece
+			switch piece {
+			case game.Tiger:
+				tigers++
+			case game.Goat:
+				goats++
+			}
+		}
+	}
+	if tigers != game.MaxTigers || goats != game.MaxGoats-state.CapturedGoats {
+		return position{}, false
+	}
+	return p, true
+}
+
+// toState 將緊湊局面轉換為遊戲狀態
+func (p *position) toState() game.GameState {
+	state := game.GameState{
+		CapturedGoats: p.captured,
+		CurrentTurn:   p.turn,
+	}
+	for sq, piece := range p.board {
+		state.Board[sq/game.BoardSize][sq%game.BoardSize] = piece
+	}
+	return state
+}
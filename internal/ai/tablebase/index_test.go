@@ -0,0 +1,109 @@
+package tablebase
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/nelawu/BagchalGolang/internal/domain/game"
+)
+
+// sampleIndices 返回表中首尾及隨機抽樣的索引
+func sampleIndices(rng *rand.Rand, captured, n int) []int64 {
+	count := positionCount(captured)
+	indices := []int64{0, 1, count - 2, count - 1}
+	for i := 0; i < n; i++ {
+		indices = append(indices, rng.Int63n(count))
+	}
+	return indices
+}
+
+func TestIndexRoundTrip(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for captured := 0; captured <= maxCaptured; captured++ {
+		for _, idx := range sampleIndices(rng, captured, 2000) {
+			p := decode(captured, idx)
+			if got := p.index(); got != idx {
+				t.Fatalf("c=%d: decode(%d).index() = %d", captured, idx, got)
+			}
+
+			state := p.toState()
+			back, ok := fromState(&state)
+			if !ok {
+				t.Fatalf("c=%d idx=%d: fromState rejected a decoded position", captured, idx)
+			}
+			if back != p {
+				t.Fatalf("c=%d idx=%d: state round-trip changed the position", captured, idx)
+			}
+		}
+	}
+}
+
+func TestFromStateRejectsPlacement(t *testing.T) {
+	state := game.NewGameState()
+	if _, ok := fromState(&state); ok {
+		t.Error("placement-phase position accepted")
+	}
+}
+
+// moveKey 以起點及終點標識移動
+type moveKey struct{ from, to int }
+
+func TestMovesMatchLegalMoves(t *testing.T) {
+	rng := rand.New(rand.NewSource(2))
+	for captured := 0; captured <= maxCaptured; captured++ {
+		for _, idx := range sampleIndices(rng, captured, 500) {
+			p := decode(captured, idx)
+			state := p.toState()
+
+			// 與 Generate 相同的走法生成：鄰點移動及虎的跳吃
+			want := map[moveKey]bool{}
+			for sq, piece := range p.board {
+				if piece != p.turn {
+					continue
+				}
+				for _, to := range neighbors[sq] {
+					if p.board[to] == game.Empty {
+						want[moveKey{sq, to}] = true
+					}
+				}
+				if piece != game.Tiger {
+					continue
+				}
+				for _, jump := range jumps[sq] {
+					if p.board[jump[0]] == game.Goat && p.board[jump[1]] == game.Empty {
+						want[moveKey{sq, jump[1]}] = true
+					}
+				}
+			}
+
+			got := map[moveKey]bool{}
+			for _, move := range game.LegalMoves(&state) {
+				got[moveKey{move.From.Y*game.BoardSize + move.From.X, move.To.Y*game.BoardSize + move.To.X}] = true
+
+				// 不吃子的移動可由後續局面的前一局面還原
+				if move.Capture != nil {
+					continue
+				}
+				child := state
+				game.ApplyMove(&child, move)
+				next, ok := fromState(&child)
+				if !ok {
+					t.Fatalf("c=%d idx=%d: move %+v left the movement phase", captured, idx, move)
+				}
+				found := false
+				forEachPredecessor(&next, func(prev int64) { found = found || prev == idx })
+				if !found {
+					t.Fatalf("c=%d idx=%d: move %+v not reversed by forEachPredecessor", captured, idx, move)
+				}
+			}
+			if len(got) != len(want) {
+				t.Fatalf("c=%d idx=%d: %d moves, LegalMoves has %d", captured, idx, len(want), len(got))
+			}
+			for key := range want {
+				if !got[key] {
+					t.Fatalf("c=%d idx=%d: move %v not in LegalMoves", captured, idx, key)
+				}
+			}
+		}
+	}
+}
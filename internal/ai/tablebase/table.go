@@ -0,0 +1,180 @@
+package tablebase
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+var ErrInvalidTable = errors.New("invalid tablebase file")
+
+// Result 表示輪到的一方在完美對弈下的結果
+type Result uint8
+
+const (
+	Unknown Result = iota // 未求解（更高層的表缺失）
+	Win
+	Loss
+	Draw
+)
+
+func (r Result) String() string {
+	switch r {
+	case Win:
+		return "win"
+	case Loss:
+		return "loss"
+	case Draw:
+		return "draw"
+	}
+	return "unknown"
+}
+
+// 每個局面以 uint16 存儲：高 2 位為結果，低 14 位為到達結果的步數
+const (
+	resultShift  = 14
+	distanceMask = 1<<resultShift - 1
+)
+
+// fileMagic 表文件標識
+var fileMagic = [4]byte{'B', 'C', 'T', 'B'}
+
+const fileVersion = 1
+
+// ioChunk 讀寫表文件時每次處理的條目數
+const ioChunk = 1 << 16
+
+// Table 被吃 c 隻羊時所有移動階段局面的結果表
+type Table struct {
+	Captured int
+	Complete bool // 更高層的表齊全時為 true，未解出的局面即為和棋
+	entries  []uint16
+}
+
+// lookup 返回索引處的結果及步數
+func (t *Table) lookup(idx int64) (Result, int) {
+	entry := t.entries[idx]
+	return Result(entry >> resultShift), int(entry & distanceMask)
+}
+
+// resolved 檢查索引處是否已有結果
+func (t *Table) resolved(idx int64) bool {
+	return Result(t.entries[idx]>>resultShift) != Unknown
+}
+
+// set 寫入索引處的結果及步數
+func (t *Table) set(idx int64, result Result, distance int) {
+	if distance > distanceMask {
+		distance = distanceMask
+	}
+	t.entries[idx] = uint16(result)<<resultShift | uint16(distance)
+}
+
+// Stats 按結果統計局面數
+func (t *Table) Stats() map[Result]int64 {
+	stats := make(map[Result]int64)
+	for _, entry := range t.entries {
+		stats[Result(entry>>resultShift)]++
+	}
+	return stats
+}
+
+// fileName 返回表文件名
+func fileName(captured int) string {
+	return fmt.Sprintf("bagchal_c%d.tb.gz", captured)
+}
+
+// Write 以 gzip 壓縮的二進制格式寫出表
+func (t *Table) Write(w io.Writer) error {
+	zw := gzip.NewWriter(w)
+	bw := bufio.NewWriter(zw)
+
+	complete := uint8(0)
+	if t.Complete {
+		complete = 1
+	}
+	header := []any{fileMagic, uint8(fileVersion), uint8(t.Captured), complete, uint64(len(t.entries))}
+	for _, field := range header {
+		if err := binary.Write(bw, binary.LittleEndian, field); err != nil {
+			return err
+		}
+	}
+	// 以重複使用的緩衝區分段寫出，binary.Write 每次調用都會分配與資料同樣大小的緩衝區
+	buf := make([]byte, 0, 2*ioChunk)
+	for start := 0; start < len(t.entries); start += ioChunk {
+		buf = buf[:0]
+		for _, entry := range t.entries[start:min(start+ioChunk, len(t.entries))] {
+			buf = binary.LittleEndian.AppendUint16(buf, entry)
+		}
+		if _, err := bw.Write(buf); err != nil {
+			return err
+		}
+	}
+	if err := bw.Flush(); err != nil {
+		return err
+	}
+	return zw.Close()
+}
+
+// ReadTable 讀取表
+func ReadTable(r io.Reader) (*Table, error) {
+	zr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, ErrInvalidTable
+	}
+	defer zr.Close()
+	br := bufio.NewReader(zr)
+
+	var magic [4]byte
+	var version, captured, complete uint8
+	var count uint64
+	for _, field := range []any{&magic, &version, &captured, &complete, &count} {
+		if err := binary.Read(br, binary.LittleEndian, field); err != nil {
+			return nil, ErrInvalidTable
+		}
+	}
+	if magic != fileMagic || version != fileVersion || int(captured) >= maxCaptured+1 ||
+		int64(count) != positionCount(int(captured)) {
+		return nil, ErrInvalidTable
+	}
+
+	t := &Table{Captured: int(captured), Complete: complete == 1, entries: make([]uint16, count)}
+	buf := make([]byte, 2*ioChunk)
+	for start := 0; start < len(t.entries); start += ioChunk {
+		chunk := t.entries[start:min(start+ioChunk, len(t.entries))]
+		if _, err := io.ReadFull(br, buf[:2*len(chunk)]); err != nil {
+			return nil, ErrInvalidTable
+		}
+		for i := range chunk {
+			chunk[i] = binary.LittleEndian.Uint16(buf[2*i:])
+		}
+	}
+	return t, nil
+}
+
+// WriteFile 將表寫入目錄
+func (t *Table) WriteFile(dir string) error {
+	f, err := os.Create(dir + string(os.PathSeparator) + fileName(t.Captured))
+	if err != nil {
+		return err
+	}
+	if err := t.Write(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// ReadTableFile 從目錄讀取被吃 c 隻羊的表，文件不存在時返回 os.ErrNotExist
+func ReadTableFile(dir string, captured int) (*Table, error) {
+	f, err := os.Open(dir + string(os.PathSeparator) + fileName(captured))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ReadTable(f)
+}
@@ -0,0 +1,101 @@
+package tablebase
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// smallTable 返回被吃 0 隻羊、部分條目已填的表
+func smallTable() *Table {
+	t := &Table{Captured: 0, Complete: true, entries: make([]uint16, positionCount(0))}
+	t.set(0, Win, 1)
+	t.set(7, Loss, 12)
+	t.set(int64(len(t.entries)-1), Draw, 0)
+	t.set(100, Win, distanceMask+5) // 超出範圍的步數被截斷
+	return t
+}
+
+func TestTableRoundTrip(t *testing.T) {
+	table := smallTable()
+	if result, distance := table.lookup(100); result != Win || distance != distanceMask {
+		t.Errorf("lookup(100) = %v %d, want win %d", result, distance, distanceMask)
+	}
+
+	var buf bytes.Buffer
+	if err := table.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+	got, err := ReadTable(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, table) {
+		t.Error("table changed after Write/ReadTable")
+	}
+
+	dir := t.TempDir()
+	if err := table.WriteFile(dir); err != nil {
+		t.Fatal(err)
+	}
+	tb, err := Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tables := tb.Tables(); len(tables) != 1 || !reflect.DeepEqual(tables[0], table) {
+		t.Error("Load did not return the written table")
+	}
+}
+
+func TestLoadEmptyDir(t *testing.T) {
+	if _, err := Load(t.TempDir()); err == nil {
+		t.Error("Load of an empty directory succeeded")
+	}
+}
+
+// gzipBytes 以 gzip 壓縮資料
+func gzipBytes(t *testing.T, fields ...any) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	for _, field := range fields {
+		if err := binary.Write(zw, binary.LittleEndian, field); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestReadTableRejectsCorruptInput(t *testing.T) {
+	count := uint64(positionCount(0))
+	var valid bytes.Buffer
+	if err := smallTable().Write(&valid); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"empty", nil},
+		{"not gzip", []byte("BCTB not a table")},
+		{"truncated gzip", valid.Bytes()[:valid.Len()/2]},
+		{"short header", gzipBytes(t, fileMagic, uint8(fileVersion))},
+		{"bad magic", gzipBytes(t, [4]byte{'X', 'X', 'X', 'X'}, uint8(fileVersion), uint8(0), uint8(1), count)},
+		{"bad version", gzipBytes(t, fileMagic, uint8(fileVersion+1), uint8(0), uint8(1), count)},
+		{"captured out of range", gzipBytes(t, fileMagic, uint8(fileVersion), uint8(maxCaptured+1), uint8(1), count)},
+		{"wrong entry count", gzipBytes(t, fileMagic, uint8(fileVersion), uint8(0), uint8(1), count-1)},
+		{"missing entries", gzipBytes(t, fileMagic, uint8(fileVersion), uint8(0), uint8(1), count, make([]uint16, 10))},
+	}
+	for _, tt := range tests {
+		if _, err := ReadTable(bytes.NewReader(tt.data)); !errors.Is(err, ErrInvalidTable) {
+			t.Errorf("%s: err = %v, want ErrInvalidTable", tt.name, err)
+		}
+	}
+}
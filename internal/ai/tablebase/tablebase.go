@@ -0,0 +1,109 @@
+package tablebase
+
+import (
+	"errors"
+	"os"
+
+	"github.com/nelawu/BagchalGolang/internal/domain/game"
+)
+
+// Tablebase 移動階段的殘局庫，按被吃的羊數分表
+type Tablebase struct {
+	tables [maxCaptured + 1]*Table
+}
+
+// New 以已生成的表創建殘局庫
+func New(tables ...*Table) *Tablebase {
+	tb := &Tablebase{}
+	for _, t := range tables {
+		tb.tables[t.Captured] = t
+	}
+	return tb
+}
+
+// Load 從目錄載入所有存在的表，一個都沒有時返回錯誤
+func Load(dir string) (*Tablebase, error) {
+	tb := &Tablebase{}
+	loaded := 0
+	for captured := 0; captured <= maxCaptured; captured++ {
+		t, err := ReadTableFile(dir, captured)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		tb.tables[captured] = t
+		loaded++
+	}
+	if loaded == 0 {
+		return nil, os.ErrNotExist
+	}
+	return tb, nil
+}
+
+// Tables 返回已載入的表
+func (tb *Tablebase) Tables() []*Table {
+	var tables []*Table
+	for _, t := range tb.tables {
+		if t != nil {
+			tables = append(tables, t)
+		}
+	}
+	return tables
+}
+
+// Probe 查詢局面對輪到的一方的結果及到達結果的步數，不在表中或未解出時返回 false
+func (tb *Tablebase) Probe(state *game.GameState) (Result, int, bool) {
+	if state.IsGameOver {
+		return Unknown, 0, false
+	}
+	p, ok := fromState(state)
+	if !ok || tb.tables[p.captured] == nil {
+		return Unknown, 0, false
+	}
+	result, distance := tb.tables[p.captured].lookup(p.index())
+	return result, distance, result != Unknown
+}
+
+// BestMove 返回完美對弈下的最佳移動：勝局選最快的勝利，敗局選最慢的失敗，和局保持和棋
+func (tb *Tablebase) BestMove(state *game.GameState) (game.Move, Result, int, bool) {
+	result, distance, ok := tb.Probe(state)
+	if !ok {
+		return game.Move{}, Unknown, 0, false
+	}
+
+	var best game.Move
+	bestDistance := -1
+	for _, move := range game.LegalMoves(state) {
+		child := *state
+		game.ApplyMove(&child, move)
+		// 走完即結束的移動必然使對方落敗
+		childResult, childDistance := Loss, 0
+		if !child.IsGameOver {
+			var known bool
+			if childResult, childDistance, known = tb.Probe(&child); !known {
+				continue
+			}
+		}
+
+		switch result {
+		case Win:
+			if childResult == Loss && (bestDistance < 0 || childDistance < bestDistance) {
+				best, bestDistance = move, childDistance
+			}
+		case Loss:
+			if childDistance > bestDistance {
+				best, bestDistance = move, childDistance
+			}
+		case Draw:
+			if childResult == Draw && bestDistance < 0 {
+				best, bestDistance = move, 0
+			}
+		}
+	}
+	if bestDistance < 0 {
+		return game.Move{}, Unknown, 0, false
+	}
+	return best, result, distance, true
+}
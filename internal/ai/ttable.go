@@ -0,0 +1,262 @@
+package ai
+
+import (
+	"math/rand"
+	"sync/atomic"
+	"unsafe"
+
+	"github.com/nelawu/BagchalGolang/internal/domain/game"
+)
+
+// DefaultTableSizeMB 置換表的預設大小
+const DefaultTableSizeMB = 32
+
+// Bound 置換表中分數的性質
+type Bound uint8
+
+const (
+	boundNone  Bound = iota
+	BoundExact       // 準確值
+	BoundLower       // 下界（發生 beta 剪枝）
+	BoundUpper       // 上界（所有移動都未超過 alpha）
+)
+
+// winThreshold 超過此絕對值的分數含有到達勝負的步數，存取置換表時需按層數調整
+const winThreshold = WinScore - 1000
+
+// noSquare 表示置換表條目沒有最佳移動
+const noSquare = 0x1f
+
+// ttEntry 置換表條目，內容壓縮為一個 64 位整數，移動以標準形的方向記錄
+// 鍵以 key^data 的形式存放：兩個字段各自以原子操作讀寫，
+// 並發寫入造成字段不一致時校驗失敗，視為未命中，因此無需加鎖
+type ttEntry struct {
+	key  atomic.Uint64
+	data atomic.Uint64
+}
+
+// ttData 條目內容的位元佈局：分數 32 位、深度 8 位、分數性質 2 位、世代 8 位、起點及終點各 5 位
+type ttData struct {
+	score      int32
+	depth      uint8
+	bound      Bound
+	generation uint8
+	from, to   uint8
+}
+
+func (d ttData) pack() uint64 {
+	return uint64(uint32(d.score)) |
+		uint64(d.depth)<<32 |
+		uint64(d.bound&0x3)<<40 |
+		uint64(d.generation)<<42 |
+		uint64(d.from&0x1f)<<50 |
+		uint64(d.to&0x1f)<<55
+}
+
+func unpack(v uint64) ttData {
+	return ttData{
+		score:      int32(uint32(v)),
+		depth:      uint8(v >> 32),
+		bound:      Bound(v>>40) & 0x3,
+		generation: uint8(v >> 42),
+		from:       uint8(v>>50) & 0x1f,
+		to:         uint8(v>>55) & 0x1f,
+	}
+}
+
+// TranspositionTable 固定大小的置換表，以對稱標準化後的局面雜湊為鍵，
+// 八種對稱變換下等價的局面共用同一條目。可在多局遊戲、多次搜索及並行搜索的各線程之間共用，
+// 讀寫不加鎖
+type TranspositionTable struct {
+	entries    []ttEntry
+	mask       uint64
+	generation atomic.Uint32 // 每次搜索遞增，用於淘汰舊條目
+}
+
+// NewTranspositionTable 創建約 sizeMB 大小的置換表，條目數取不超過該大小的2的冪
+func NewTranspositionTable(sizeMB int) *TranspositionTable {
+	if sizeMB <= 0 {
+		sizeMB = DefaultTableSizeMB
+	}
+	count := uint64(1)
+	for count*2*ttEntrySize <= uint64(sizeMB)<<20 {
+		count *= 2
+	}
+	return &TranspositionTable{
+		entries: make([]ttEntry, count),
+		mask:    count - 1,
+	}
+}
+
+// ttEntrySize 每個條目佔用的位元組數
+const ttEntrySize = uint64(unsafe.Sizeof(ttEntry{}))
+
+// Size 返回條目數
+func (t *TranspositionTable) Size() int {
+	return len(t.entries)
+}
+
+// Clear 清空置換表，不可與搜索同時進行
+func (t *TranspositionTable) Clear() {
+	for i := range t.entries {
+		t.entries[i].key.Store(0)
+		t.entries[i].data.Store(0)
+	}
+	t.generation.Store(0)
+}
+
+// newSearch 在每次搜索開始時調用，使之前搜索留下的條目優先被替換
+func (t *TranspositionTable) newSearch() {
+	t.generation.Add(1)
+}
+
+// load 讀取條目，鍵不符或內容不一致時返回 false
+func (t *TranspositionTable) load(key uint64) (ttData, bool) {
+	slot := &t.entries[key&t.mask]
+	data := slot.data.Load()
+	if slot.key.Load()^data != key {
+		return ttData{}, false
+	}
+	entry := unpack(data)
+	return entry, entry.bound != boundNone
+}
+
+// probe 查詢條目，返回的分數已按層數還原，移動已變換回原局面的方向
+func (t *TranspositionTable) probe(key uint64, sym game.Symmetry, ply int) (score, depth int, bound Bound, move *game.Move, ok bool) {
+	entry, ok := t.load(key)
+	if !ok {
+		return 0, 0, boundNone, nil, false
+	}
+
+	if entry.from != noSquare {
+		inverse := sym.Inverse()
+		m := game.Move{
+			From: inverse.Apply(squarePosition(int(entry.from))),
+			To:   inverse.Apply(squarePosition(int(entry.to))),
+		}
+		move = &m
+	}
+	return scoreFromTable(int(entry.score), ply), int(entry.depth), entry.bound, move, true
+}
+
+// store 記錄搜索結果，替換策略：空位、同一局面、舊搜索留下的條目，或深度不小於原條目
+// 並發寫入同一位置時後寫者勝出，不影響正確性
+func (t *TranspositionTable) store(key uint64, sym game.Symmetry, ply, depth, score int, bound Bound, move *game.Move) {
+	generation := uint8(t.generation.Load())
+	entry := ttData{
+		score:      int32(scoreToTable(score, ply)),
+		depth:      uint8(depth),
+		bound:      bound,
+		generation: generation,
+		from:       noSquare,
+		to:         noSquare,
+	}
+	if move != nil {
+		entry.from = uint8(squareIndex(sym.Apply(move.From)))
+		entry.to = uint8(squareIndex(sym.Apply(move.To)))
+	}
+
+	slot := &t.entries[key&t.mask]
+	data := slot.data.Load()
+	existing := unpack(data)
+	sameKey := slot.key.Load()^data == key
+	if existing.bound != boundNone && !sameKey && existing.generation == generation && existing.depth > entry.depth {
+		return
+	}
+	if sameKey && move == nil {
+		// 保留同一局面之前找到的最佳移動，供移動排序使用
+		entry.from, entry.to = existing.from, existing.to
+	}
+
+	packed := entry.pack()
+	slot.key.Store(key ^ packed)
+	slot.data.Store(packed)
+}
+
+// scoreToTable 將勝負分數從「距根節點」轉換為「距本節點」，使不同路徑到達的同一局面可共用
+func scoreToTable(score, ply int) int {
+	switch {
+	case score > winThreshold:
+		return score + ply
+	case score < -winThreshold:
+		return score - ply
+	}
+	return score
+}
+
+// scoreFromTable 是 scoreToTable 的逆轉換
+func scoreFromTable(score, ply int) int {
+	switch {
+	case score > winThreshold:
+		return score - ply
+	case score < -winThreshold:
+		return score + ply
+	}
+	return score
+}
+
+// squarePosition 返回序號對應的位置
+func squarePosition(index int) game.Position {
+	return game.Position{X: index % game.BoardSize, Y: index / game.BoardSize}
+}
+
+// Zobrist 雜湊的隨機數，以固定種子生成使雜湊在各次執行間一致
+var (
+	zobristPiece    [squareCount][2]uint64 // [點][虎、羊]
+	zobristInHand   [game.MaxGoats + 1]uint64
+	zobristCaptured [game.CapturesToWin + 1]uint64
+	zobristGoatTurn uint64
+	symmetrySquares [len(game.Symmetries)][squareCount]int // [對稱][點] -> 變換後的點
+)
+
+func init() {
+	rng := rand.New(rand.NewSource(0x6261676368616c))
+	for i := range zobristPiece {
+		zobristPiece[i][0] = rng.Uint64()
+		zobristPiece[i][1] = rng.Uint64()
+	}
+	for i := range zobristInHand {
+		zobristInHand[i] = rng.Uint64()
+	}
+	for i := range zobristCaptured {
+		zobristCaptured[i] = rng.Uint64()
+	}
+	zobristGoatTurn = rng.Uint64()
+
+	for _, sym := range game.Symmetries {
+		for i := 0; i < squareCount; i++ {
+			symmetrySquares[sym][i] = squareIndex(sym.Apply(squarePosition(i)))
+		}
+	}
+}
+
+// canonicalHash 返回八種對稱變換中最小的局面雜湊，以及把局面變換為該標準形的對稱
+func canonicalHash(state *game.GameState) (uint64, game.Symmetry) {
+	var hashes [len(game.Symmetries)]uint64
+	for y := 0; y < game.BoardSize; y++ {
+		for x := 0; x < game.BoardSize; x++ {
+			piece := state.Board[y][x]
+			if piece == game.Empty {
+				continue
+			}
+			square := y*game.BoardSize + x
+			for sym := range hashes {
+				hashes[sym] ^= zobristPiece[symmetrySquares[sym][square]][piece-game.Tiger]
+			}
+		}
+	}
+
+	best, bestSym := hashes[0], game.Identity
+	for sym, hash := range hashes[1:] {
+		if hash < best {
+			best, bestSym = hash, game.Symmetry(sym+1)
+		}
+	}
+
+	best ^= zobristInHand[min(state.GoatsInHand, game.MaxGoats)]
+	best ^= zobristCaptured[min(state.CapturedGoats, game.CapturesToWin)]
+	if state.CurrentTurn == game.Goat {
+		best ^= zobristGoatTurn
+	}
+	return best, bestSym
+}
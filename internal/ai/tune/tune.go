@@ -0,0 +1,172 @@
+// Package tune 以對局結果擬合靜態評估的權重（Texel 調參法）
+//
+// 每個局面標記所屬對局的最終結果（虎勝 1、和 0.5、羊勝 0），
+// 以 sigmoid(K * 評估分數) 預測虎方得分，並以局部搜索最小化預測的均方誤差。
+package tune
+
+import (
+	"math"
+	"math/rand"
+
+	"github.com/nelawu/BagchalGolang/internal/ai"
+	"github.com/nelawu/BagchalGolang/internal/domain/game"
+)
+
+// Sample 一個已標記結果的局面，只保留評估所需的特徵
+type Sample struct {
+	Features ai.Features
+	Result   float64 // 虎方得分：勝 1、和 0.5、負 0
+}
+
+// Samples 從已結束的遊戲中取出局面並以最終結果標記
+// 跳過開局的 skipPlies 步及虎方可立即吃子的非靜止局面，這些局面的靜態評估不可靠
+func Samples(g *game.Game, skipPlies int) []Sample {
+	if !g.State.IsGameOver {
+		return nil
+	}
+	result := 0.5
+	switch g.State.Winner {
+	case game.Tiger:
+		result = 1
+	case game.Goat:
+		result = 0
+	}
+
+	var samples []Sample
+	state := game.NewGameState()
+	for ply, move := range g.History {
+		if ply >= skipPlies && !state.IsGameOver {
+			features := ai.ExtractFeatures(&state)
+			if state.CurrentTurn != game.Tiger || features.GoatsThreatened == 0 {
+				samples = append(samples, Sample{Features: features, Result: result})
+			}
+		}
+		game.ApplyMove(&state, move)
+	}
+	return samples
+}
+
+// Options 調參參數
+type Options struct {
+	MaxPasses int   // 局部搜索的最大輪數
+	Steps     []int // 每輪依次嘗試的步長，由大到小
+	Fixed     []int // 不調整的特徵序號（按 ai.FeatureNames 的順序）
+}
+
+// DefaultOptions 返回預設參數；被吃羊數的權重固定為 100 作為評估分數的單位
+func DefaultOptions() Options {
+	return Options{
+		MaxPasses: 100,
+		Steps:     []int{16, 4, 1},
+		Fixed:     []int{0},
+	}
+}
+
+// Result 調參結果
+type Result struct {
+	Weights      ai.Weights
+	K            float64 // sigmoid 的縮放係數
+	InitialError float64
+	FinalError   float64
+	Passes       int
+}
+
+// Tune 從初始權重開始做局部搜索：每輪對每個權重嘗試加減步長，誤差下降即保留，
+// 直到一輪中所有步長都無法改進或達到最大輪數
+func Tune(samples []Sample, initial ai.Weights, options Options) *Result {
+	fixed := make(map[int]bool)
+	for _, i := range options.Fixed {
+		fixed[i] = true
+	}
+
+	weights := initial.Values()
+	k := FitK(samples, initial)
+	best := Error(samples, initial, k)
+	result := &Result{K: k, InitialError: best}
+
+	for _, step := range options.Steps {
+		for pass := 0; pass < options.MaxPasses; pass++ {
+			result.Passes++
+			improved := false
+			for i := range weights {
+				if fixed[i] {
+					continue
+				}
+				for _, delta := range []int{step, -step} {
+					weights[i] += delta
+					if err := Error(samples, ai.WeightsFromValues(weights), k); err < best {
+						best, improved = err, true
+						break
+					}
+					weights[i] -= delta
+				}
+			}
+			if !improved {
+				break
+			}
+		}
+	}
+
+	result.Weights = ai.WeightsFromValues(weights)
+	result.FinalError = best
+	return result
+}
+
+// FitK 以黃金分割搜索找出使初始權重誤差最小的縮放係數，之後調參時固定不變
+func FitK(samples []Sample, weights ai.Weights) float64 {
+	const ratio = 0.6180339887498949
+	lo, hi := 0.0001, 0.1
+	for i := 0; i < 60; i++ {
+		a := hi - ratio*(hi-lo)
+		b := lo + ratio*(hi-lo)
+		if Error(samples, weights, a) < Error(samples, weights, b) {
+			hi = b
+		} else {
+			lo = a
+		}
+	}
+	return (lo + hi) / 2
+}
+
+// Error 返回以權重預測虎方得分的均方誤差
+func Error(samples []Sample, weights ai.Weights, k float64) float64 {
+	if len(samples) == 0 {
+		return 0
+	}
+	values := weights.Values()
+	total := 0.0
+	for _, sample := range samples {
+		features := sample.Features.Values()
+		score := 0
+		for i := range features {
+			score += features[i] * values[i]
+		}
+		diff := sample.Result - 1/(1+math.Exp(-k*float64(score)))
+		total += diff * diff
+	}
+	return total / float64(len(samples))
+}
+
+// SelfPlay 以固定搜索限制讓引擎與自己對弈一局，前 randomPlies 步隨機以增加局面多樣性，
+// 超過 maxPlies 步時判和；引擎給出非法移動時返回錯誤，以免把錯誤的局面當作樣本
+func SelfPlay(engine *ai.Engine, limits ai.Limits, randomPlies, maxPlies int, rng *rand.Rand) (*game.Game, error) {
+	g := game.NewMatchedGame(game.AIPlayerID(0), game.AIPlayerID(0), nil, false)
+	for len(g.History) < maxPlies && !g.State.IsGameOver {
+		var move game.Move
+		if len(g.History) < randomPlies {
+			moves := game.LegalMoves(&g.State)
+			move = moves[rng.Intn(len(moves))]
+		} else {
+			analysis := engine.Analyze(g.State, limits)
+			if analysis.BestMove == nil {
+				break
+			}
+			move = *analysis.BestMove
+		}
+		if err := g.MakeMove(move); err != nil {
+			return nil, err
+		}
+	}
+	g.State.IsGameOver = true
+	return g, nil
+}
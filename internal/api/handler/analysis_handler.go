@@ -0,0 +1,178 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nelawu/BagchalGolang/internal/api/middleware"
+	"github.com/nelawu/BagchalGolang/internal/domain/analysis"
+	"github.com/nelawu/BagchalGolang/internal/domain/game"
+)
+
+type AnalysisHandler struct {
+	analysisService *analysis.AnalysisService
+}
+
+func NewAnalysisHandler(analysisService *analysis.AnalysisService) *AnalysisHandler {
+	return &AnalysisHandler{
+		analysisService: analysisService,
+	}
+}
+
+// RegisterRoutes 註冊路由，引擎分析耗費資源，需身份驗證
+func (h *AnalysisHandler) RegisterRoutes(router *gin.Engine, requireAuth gin.HandlerFunc) {
+	router.POST("/api/analysis", requireAuth, h.analyzePosition)
+	router.POST("/api/analysis/explain", requireAuth, h.explainPosition)
+	router.GET("/api/games/:id/analysis", requireAuth, h.analyzeGame)
+	router.GET("/api/games/:id/annotation", h.getAnnotation)
+	router.POST("/api/games/:id/annotation", requireAuth, h.requestAnnotation)
+	router.GET("/api/games/:id/hint", requireAuth, h.hint)
+}
+
+// AnalyzePositionRequest 局面分析請求
+type AnalyzePositionRequest struct {
+	State  game.GameState `json:"state" binding:"required"`
+	Depth  int            `json:"depth"`  // 搜索深度，0 表示預設
+	TimeMs int            `json:"timeMs"` // 時間預算（毫秒），0 表示預設
+}
+
+// analyzePosition 分析提交的局面
+func (h *AnalysisHandler) analyzePosition(c *gin.Context) {
+	var req AnalyzePositionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "無效的請求數據"})
+		return
+	}
+
+	result, err := h.analysisService.AnalyzePosition(middleware.PlayerID(c), req.State, req.Depth, time.Duration(req.TimeMs)*time.Millisecond)
+	if err != nil {
+		switch err {
+		case analysis.ErrInvalidPosition:
+			c.JSON(http.StatusBadRequest, gin.H{"error": "無效的局面"})
+		case game.ErrGameNotOver:
+			c.JSON(http.StatusConflict, gin.H{"error": "不能分析自己進行中的遊戲，請使用提示"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "分析失敗"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// ExplainPositionRequest 靜態評估分解請求
+type ExplainPositionRequest struct {
+	State game.GameState `json:"state" binding:"required"`
+}
+
+// explainPosition 返回提交局面的靜態評估中每個特徵的貢獻
+func (h *AnalysisHandler) explainPosition(c *gin.Context) {
+	var req ExplainPositionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "無效的請求數據"})
+		return
+	}
+
+	explanation, err := h.analysisService.ExplainPosition(req.State)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "無效的局面"})
+		return
+	}
+
+	c.JSON(http.StatusOK, explanation)
+}
+
+// analyzeGame 分析遊戲中的局面，ply 省略時分析當前局面
+func (h *AnalysisHandler) analyzeGame(c *gin.Context) {
+	ply := -1
+	if text := c.Query("ply"); text != "" {
+		var err error
+		if ply, err = strconv.Atoi(text); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "無效的步數"})
+			return
+		}
+	}
+	depth, _ := strconv.Atoi(c.Query("depth"))
+	timeMs, _ := strconv.Atoi(c.Query("timeMs"))
+
+	result, err := h.analysisService.AnalyzeGame(c.Param("id"), middleware.PlayerID(c), ply, depth, time.Duration(timeMs)*time.Millisecond)
+	if err != nil {
+		switch err {
+		case game.ErrGameNotFound:
+			c.JSON(http.StatusNotFound, gin.H{"error": "遊戲不存在"})
+		case game.ErrGameNotOver:
+			c.JSON(http.StatusConflict, gin.H{"error": "不能分析自己進行中的遊戲，請使用提示"})
+		case game.ErrInvalidPly:
+			c.JSON(http.StatusBadRequest, gin.H{"error": "步數超出範圍"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "分析失敗"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// getAnnotation 獲取賽後註解
+func (h *AnalysisHandler) getAnnotation(c *gin.Context) {
+	annotation, err := h.analysisService.GetAnnotation(c.Param("id"))
+	if err != nil {
+		switch err {
+		case game.ErrGameNotFound:
+			c.JSON(http.StatusNotFound, gin.H{"error": "遊戲不存在"})
+		case analysis.ErrAnnotationNotReady:
+			c.JSON(http.StatusNotFound, gin.H{"error": "賽後註解尚未生成"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "獲取註解失敗"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, annotation)
+}
+
+// requestAnnotation 重新生成賽後註解，完成後可通過 GET 獲取
+func (h *AnalysisHandler) requestAnnotation(c *gin.Context) {
+	if err := h.analysisService.RequestAnnotation(c.Param("id")); err != nil {
+		switch err {
+		case game.ErrGameNotFound:
+			c.JSON(http.StatusNotFound, gin.H{"error": "遊戲不存在"})
+		case game.ErrGameNotOver:
+			c.JSON(http.StatusConflict, gin.H{"error": "遊戲尚未結束"})
+		case analysis.ErrQueueFull:
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": "分析隊列已滿，請稍後再試"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "生成註解失敗"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"message": "已加入分析隊列"})
+}
+
+// hint 為輪到的玩家提供建議移動，strength 為 1-3
+func (h *AnalysisHandler) hint(c *gin.Context) {
+	strength, _ := strconv.Atoi(c.Query("strength"))
+	hint, err := h.analysisService.Hint(c.Param("id"), middleware.PlayerID(c), strength)
+	if err != nil {
+		switch err {
+		case analysis.ErrInvalidStrength:
+			c.JSON(http.StatusBadRequest, gin.H{"error": "提示強度必須在1-3之間"})
+		case game.ErrGameNotFound:
+			c.JSON(http.StatusNotFound, gin.H{"error": "遊戲不存在"})
+		case game.ErrForbidden:
+			c.JSON(http.StatusForbidden, gin.H{"error": "只有遊戲參與者可以獲取提示"})
+		case game.ErrNotPlayersTurn:
+			c.JSON(http.StatusConflict, gin.H{"error": "還沒輪到你"})
+		case game.ErrGameOver:
+			c.JSON(http.StatusConflict, gin.H{"error": "遊戲已結束"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "獲取提示失敗"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, hint)
+}
@@ -0,0 +1,64 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nelawu/BagchalGolang/internal/auth"
+	"github.com/nelawu/BagchalGolang/internal/domain/player"
+)
+
+type AuthHandler struct {
+	playerService *player.PlayerService
+	tokenIssuer   *auth.TokenIssuer
+}
+
+func NewAuthHandler(playerService *player.PlayerService, tokenIssuer *auth.TokenIssuer) *AuthHandler {
+	return &AuthHandler{
+		playerService: playerService,
+		tokenIssuer:   tokenIssuer,
+	}
+}
+
+// RegisterRoutes 註冊路由
+func (h *AuthHandler) RegisterRoutes(router *gin.Engine) {
+	router.POST("/api/auth/sessions", h.createSession)
+}
+
+// CreateSessionRequest 登入請求
+type CreateSessionRequest struct {
+	Username string `json:"username" binding:"required"`
+	APIKey   string `json:"apiKey" binding:"required"`
+}
+
+// createSession 使用API密鑰換取會話令牌
+func (h *AuthHandler) createSession(c *gin.Context) {
+	var req CreateSessionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "無效的請求數據"})
+		return
+	}
+
+	p, err := h.playerService.Authenticate(req.Username, req.APIKey)
+	if err != nil {
+		switch err {
+		case player.ErrInvalidCredentials:
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "用戶名或API密鑰錯誤"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "登入失敗"})
+		}
+		return
+	}
+
+	token, expiresAt, err := h.tokenIssuer.Issue(p.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "簽發令牌失敗"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"token":     token,
+		"expiresAt": expiresAt,
+		"player":    p,
+	})
+}
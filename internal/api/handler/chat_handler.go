@@ -0,0 +1,146 @@
+package handler
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nelawu/BagchalGolang/internal/api/middleware"
+	"github.com/nelawu/BagchalGolang/internal/domain/chat"
+	"github.com/nelawu/BagchalGolang/internal/domain/game"
+)
+
+type ChatHandler struct {
+	chatService *chat.ChatService
+}
+
+func NewChatHandler(chatService *chat.ChatService) *ChatHandler {
+	return &ChatHandler{
+		chatService: chatService,
+	}
+}
+
+// RegisterRoutes 註冊路由，聊天相關操作均需身份驗證
+func (h *ChatHandler) RegisterRoutes(router *gin.Engine, requireAuth gin.HandlerFunc) {
+	gameGroup := router.Group("/api/games", requireAuth)
+	{
+		gameGroup.GET("/:id/chat", h.listMessages)
+		gameGroup.POST("/:id/chat", h.postMessage)
+		gameGroup.GET("/:id/chat/stream", h.streamMessages)
+	}
+
+	muteGroup := router.Group("/api/chat/mutes", requireAuth)
+	{
+		muteGroup.GET("", h.listMuted)
+		muteGroup.PUT("/:playerID", h.mute)
+		muteGroup.DELETE("/:playerID", h.unmute)
+	}
+}
+
+// PostMessageRequest 發送訊息請求
+type PostMessageRequest struct {
+	Text string `json:"text" binding:"required"`
+}
+
+// postMessage 發送聊天訊息
+func (h *ChatHandler) postMessage(c *gin.Context) {
+	var req PostMessageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "無效的請求數據"})
+		return
+	}
+
+	message, err := h.chatService.PostMessage(c.Param("id"), middleware.PlayerID(c), req.Text)
+	if err != nil {
+		switch err {
+		case game.ErrGameNotFound:
+			c.JSON(http.StatusNotFound, gin.H{"error": "遊戲不存在"})
+		case chat.ErrInvalidMessage:
+			c.JSON(http.StatusBadRequest, gin.H{"error": "訊息不能為空且不超過500字"})
+		case chat.ErrRateLimited:
+			c.JSON(http.StatusTooManyRequests, gin.H{"error": "發送訊息過於頻繁"})
+		case chat.ErrMessageRejected:
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "訊息未通過審核"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "發送訊息失敗"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, message)
+}
+
+// listMessages 獲取聊天記錄，after 為上次收到的最後一條訊息ID
+func (h *ChatHandler) listMessages(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.Query("limit"))
+	messages, err := h.chatService.ListMessages(c.Param("id"), middleware.PlayerID(c), c.Query("after"), limit)
+	if err != nil {
+		switch err {
+		case game.ErrGameNotFound:
+			c.JSON(http.StatusNotFound, gin.H{"error": "遊戲不存在"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "獲取聊天記錄失敗"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, messages)
+}
+
+// streamMessages 以 Server-Sent Events 即時推送聊天訊息
+func (h *ChatHandler) streamMessages(c *gin.Context) {
+	messages, cancel, err := h.chatService.Subscribe(c.Param("id"), middleware.PlayerID(c))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "遊戲不存在"})
+		return
+	}
+	defer cancel()
+
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case message := <-messages:
+			c.SSEvent("message", message)
+			return true
+		case <-c.Request.Context().Done():
+			return false
+		}
+	})
+}
+
+// listMuted 列出已屏蔽的玩家
+func (h *ChatHandler) listMuted(c *gin.Context) {
+	muted, err := h.chatService.ListMuted(middleware.PlayerID(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "獲取屏蔽列表失敗"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"muted": muted})
+}
+
+// mute 屏蔽玩家的聊天訊息
+func (h *ChatHandler) mute(c *gin.Context) {
+	h.setMuted(c, true)
+}
+
+// unmute 取消屏蔽玩家
+func (h *ChatHandler) unmute(c *gin.Context) {
+	h.setMuted(c, false)
+}
+
+// setMuted 設置屏蔽狀態
+func (h *ChatHandler) setMuted(c *gin.Context, muted bool) {
+	err := h.chatService.SetMuted(middleware.PlayerID(c), c.Param("playerID"), muted)
+	if err != nil {
+		switch err {
+		case chat.ErrCannotMuteSelf:
+			c.JSON(http.StatusBadRequest, gin.H{"error": "不能屏蔽自己"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "設置屏蔽失敗"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"playerId": c.Param("playerID"), "muted": muted})
+}
@@ -4,6 +4,7 @@ import (
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/nelawu/BagchalGolang/internal/api/middleware"
 	"github.com/nelawu/BagchalGolang/internal/domain/game"
 )
 
@@ -17,23 +18,25 @@ func NewGameHandler(gameService *game.GameService) *GameHandler {
 	}
 }
 
-// RegisterRoutes 註冊路由
-func (h *GameHandler) RegisterRoutes(router *gin.Engine) {
+// RegisterRoutes 註冊路由，除查看遊戲外均需身份驗證
+func (h *GameHandler) RegisterRoutes(router *gin.Engine, requireAuth gin.HandlerFunc) {
 	gameGroup := router.Group("/api/games")
 	{
-		gameGroup.POST("", h.createGame)
+		gameGroup.POST("", requireAuth, h.createGame)
 		gameGroup.GET("/:id", h.getGame)
-		gameGroup.POST("/:id/moves", h.makeMove)
-		gameGroup.GET("/player/:playerID", h.listPlayerGames)
-		gameGroup.DELETE("/:id", h.deleteGame)
+		gameGroup.POST("/:id/moves", requireAuth, h.makeMove)
+		gameGroup.GET("/player/:playerID", requireAuth, h.listPlayerGames)
+		gameGroup.DELETE("/:id", requireAuth, h.deleteGame)
 	}
 }
 
 // CreateGameRequest 創建遊戲請求
 type CreateGameRequest struct {
-	PlayerID string `json:"playerId"`
+	PlayerID string `json:"playerId"` // 可省略，預設為已驗證的玩家
 	IsAIGame bool   `json:"isAIGame"`
 	AILevel  int    `json:"aiLevel"`
+	Rated    bool   `json:"rated"` // 是否計入積分
+	Seed     *int64 `json:"seed"`  // AI隨機選擇的種子，可省略，計分遊戲不能指定；指定之前遊戲的種子並走相同的棋可重現該局
 }
 
 // createGame 創建新遊戲
@@ -44,15 +47,32 @@ func (h *GameHandler) createGame(c *gin.Context) {
 		return
 	}
 
+	// 只能以自己的身份創建遊戲
+	playerID := middleware.PlayerID(c)
+	if req.PlayerID == "" {
+		req.PlayerID = playerID
+	}
+	if req.PlayerID != playerID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "無權以其他玩家身份創建遊戲"})
+		return
+	}
+
 	// 驗證AI難度級別
 	if req.IsAIGame && (req.AILevel < 1 || req.AILevel > 3) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "無效的AI難度級別（應為1-3）"})
 		return
 	}
 
-	newGame, err := h.gameService.CreateGame(req.PlayerID, req.IsAIGame, req.AILevel)
+	newGame, err := h.gameService.CreateGame(req.PlayerID, req.IsAIGame, req.AILevel, req.Rated, req.Seed)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "創建遊戲失敗"})
+		switch err {
+		case game.ErrPlayerNotFound:
+			c.JSON(http.StatusNotFound, gin.H{"error": "玩家不存在"})
+		case game.ErrRatedSeed:
+			c.JSON(http.StatusBadRequest, gin.H{"error": "計分遊戲不能指定種子"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "創建遊戲失敗"})
+		}
 		return
 	}
 
@@ -93,11 +113,15 @@ func (h *GameHandler) makeMove(c *gin.Context) {
 		PieceType: req.PieceType,
 	}
 
-	updatedGame, err := h.gameService.MakeMove(gameID, move)
+	updatedGame, err := h.gameService.MakeMove(gameID, middleware.PlayerID(c), move)
 	if err != nil {
 		switch err {
 		case game.ErrGameNotFound:
 			c.JSON(http.StatusNotFound, gin.H{"error": "遊戲不存在"})
+		case game.ErrForbidden:
+			c.JSON(http.StatusForbidden, gin.H{"error": "無權操作此遊戲"})
+		case game.ErrNotPlayersTurn:
+			c.JSON(http.StatusForbidden, gin.H{"error": "不能移動對方的棋子"})
 		case game.ErrInvalidMove:
 			c.JSON(http.StatusBadRequest, gin.H{"error": "無效的移動"})
 		case game.ErrGameOver:
@@ -114,9 +138,19 @@ func (h *GameHandler) makeMove(c *gin.Context) {
 // listPlayerGames 列出玩家的所有遊戲
 func (h *GameHandler) listPlayerGames(c *gin.Context) {
 	playerID := c.Param("playerID")
+	if playerID != middleware.PlayerID(c) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "無權查看其他玩家的遊戲列表"})
+		return
+	}
+
 	games, err := h.gameService.ListPlayerGames(playerID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "獲取遊戲列表失敗"})
+		switch err {
+		case game.ErrPlayerNotFound:
+			c.JSON(http.StatusNotFound, gin.H{"error": "玩家不存在"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "獲取遊戲列表失敗"})
+		}
 		return
 	}
 
@@ -126,9 +160,16 @@ func (h *GameHandler) listPlayerGames(c *gin.Context) {
 // deleteGame 刪除遊戲
 func (h *GameHandler) deleteGame(c *gin.Context) {
 	gameID := c.Param("id")
-	err := h.gameService.DeleteGame(gameID)
+	err := h.gameService.DeleteGame(gameID, middleware.PlayerID(c))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "刪除遊戲失敗"})
+		switch err {
+		case game.ErrGameNotFound:
+			c.JSON(http.StatusNotFound, gin.H{"error": "遊戲不存在"})
+		case game.ErrForbidden:
+			c.JSON(http.StatusForbidden, gin.H{"error": "無權刪除此遊戲"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "刪除遊戲失敗"})
+		}
 		return
 	}
 
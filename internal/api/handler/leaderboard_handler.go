@@ -0,0 +1,50 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nelawu/BagchalGolang/internal/domain/leaderboard"
+)
+
+type LeaderboardHandler struct {
+	leaderboardService *leaderboard.Service
+}
+
+func NewLeaderboardHandler(leaderboardService *leaderboard.Service) *LeaderboardHandler {
+	return &LeaderboardHandler{
+		leaderboardService: leaderboardService,
+	}
+}
+
+// RegisterRoutes 註冊路由
+func (h *LeaderboardHandler) RegisterRoutes(router *gin.Engine) {
+	router.GET("/api/leaderboards/:metric", h.getLeaderboard)
+}
+
+// getLeaderboard 獲取排行榜
+// 查詢參數：period（daily、monthly、all_time）、timeControl（all、untimed 或如 300_5，即 300 秒加 5 秒）、limit、minGames
+func (h *LeaderboardHandler) getLeaderboard(c *gin.Context) {
+	period := leaderboard.Period(c.DefaultQuery("period", string(leaderboard.PeriodAllTime)))
+	limit, _ := strconv.Atoi(c.Query("limit"))
+	minGames := leaderboard.DefaultMinGames
+	if value, err := strconv.Atoi(c.Query("minGames")); err == nil && value >= 0 {
+		minGames = value
+	}
+
+	board, err := h.leaderboardService.GetLeaderboard(leaderboard.Metric(c.Param("metric")), period, c.Query("timeControl"), limit, minGames)
+	if err != nil {
+		switch err {
+		case leaderboard.ErrInvalidMetric:
+			c.JSON(http.StatusBadRequest, gin.H{"error": "無效的排行榜指標"})
+		case leaderboard.ErrInvalidPeriod:
+			c.JSON(http.StatusBadRequest, gin.H{"error": "無效的統計時段"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "獲取排行榜失敗"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, board)
+}
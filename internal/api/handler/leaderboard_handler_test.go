@@ -0,0 +1,53 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nelawu/BagchalGolang/internal/domain/game"
+	"github.com/nelawu/BagchalGolang/internal/domain/leaderboard"
+)
+
+type fixedRatings struct{}
+
+func (fixedRatings) Rating(playerID string, side game.PieceType) float64 { return 1500 }
+
+func TestLeaderboardTimeControlQuery(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	service := leaderboard.NewService(fixedRatings{})
+	timed := game.NewMatchedGame("player_a", "player_b", &game.TimeControl{InitialSeconds: 300, IncrementSeconds: 5}, true)
+	timed.State.IsGameOver, timed.State.Winner = true, game.Tiger
+	service.HandleGameOver(timed)
+
+	router := gin.New()
+	NewLeaderboardHandler(service).RegisterRoutes(router)
+
+	tests := []struct {
+		query   string
+		entries int
+	}{
+		{"timeControl=300_5", 2},
+		{"timeControl=all", 2},
+		{"", 2},
+		{"timeControl=untimed", 0},
+		{"timeControl=600_0", 0},
+	}
+	for _, tt := range tests {
+		recorder := httptest.NewRecorder()
+		request := httptest.NewRequest(http.MethodGet, "/api/leaderboards/games_played?"+tt.query, nil)
+		router.ServeHTTP(recorder, request)
+		if recorder.Code != http.StatusOK {
+			t.Fatalf("%q: status %d: %s", tt.query, recorder.Code, recorder.Body)
+		}
+		var board leaderboard.Leaderboard
+		if err := json.Unmarshal(recorder.Body.Bytes(), &board); err != nil {
+			t.Fatal(err)
+		}
+		if len(board.Entries) != tt.entries {
+			t.Errorf("%q: %d entries, want %d", tt.query, len(board.Entries), tt.entries)
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nelawu/BagchalGolang/internal/api/middleware"
+	"github.com/nelawu/BagchalGolang/internal/domain/game"
+	"github.com/nelawu/BagchalGolang/internal/domain/matchmaking"
+)
+
+// maxWaitSeconds 長輪詢的最長等待時間
+const maxWaitSeconds = 60
+
+type MatchmakingHandler struct {
+	matchmakingService *matchmaking.Service
+}
+
+func NewMatchmakingHandler(matchmakingService *matchmaking.Service) *MatchmakingHandler {
+	return &MatchmakingHandler{
+		matchmakingService: matchmakingService,
+	}
+}
+
+// RegisterRoutes 註冊路由，所有配對操作均需身份驗證
+func (h *MatchmakingHandler) RegisterRoutes(router *gin.Engine, requireAuth gin.HandlerFunc) {
+	matchGroup := router.Group("/api/matchmaking", requireAuth)
+	{
+		matchGroup.POST("/tickets", h.enqueue)
+		matchGroup.GET("/tickets/:id", h.getTicket)
+		matchGroup.DELETE("/tickets/:id", h.cancel)
+	}
+}
+
+// EnqueueRequest 加入配對隊列請求，玩家為已驗證的身份
+type EnqueueRequest struct {
+	Side        game.PieceType   `json:"side"` // 0 不限，1 虎，2 羊
+	TimeControl game.TimeControl `json:"timeControl"`
+}
+
+// enqueue 加入配對隊列
+func (h *MatchmakingHandler) enqueue(c *gin.Context) {
+	var req EnqueueRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "無效的請求數據"})
+		return
+	}
+
+	ticket, err := h.matchmakingService.Enqueue(middleware.PlayerID(c), req.Side, req.TimeControl)
+	if err != nil {
+		switch err {
+		case matchmaking.ErrInvalidSide:
+			c.JSON(http.StatusBadRequest, gin.H{"error": "無效的陣營"})
+		case game.ErrPlayerNotFound:
+			c.JSON(http.StatusNotFound, gin.H{"error": "玩家不存在"})
+		case matchmaking.ErrAlreadyQueued:
+			c.JSON(http.StatusConflict, gin.H{"error": "玩家已在配對隊列中"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "加入配對失敗"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, ticket)
+}
+
+// getTicket 獲取配對票狀態，帶 wait 參數時長輪詢直到配對成功或超時
+func (h *MatchmakingHandler) getTicket(c *gin.Context) {
+	ticketID := c.Param("id")
+
+	wait, _ := strconv.Atoi(c.Query("wait"))
+	if wait > maxWaitSeconds {
+		wait = maxWaitSeconds
+	}
+
+	var ticket *matchmaking.Ticket
+	var err error
+	if wait > 0 {
+		ticket, err = h.matchmakingService.Wait(c.Request.Context(), ticketID, time.Duration(wait)*time.Second)
+	} else {
+		ticket, err = h.matchmakingService.Get(ticketID)
+	}
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "配對票不存在"})
+		return
+	}
+
+	c.JSON(http.StatusOK, ticket)
+}
+
+// cancel 取消配對
+func (h *MatchmakingHandler) cancel(c *gin.Context) {
+	ticket, err := h.matchmakingService.Cancel(c.Param("id"), middleware.PlayerID(c))
+	if err != nil {
+		switch err {
+		case matchmaking.ErrTicketNotFound:
+			c.JSON(http.StatusNotFound, gin.H{"error": "配對票不存在"})
+		case matchmaking.ErrNotTicketOwner:
+			c.JSON(http.StatusForbidden, gin.H{"error": "無權取消其他玩家的配對"})
+		case matchmaking.ErrNotWaiting:
+			c.JSON(http.StatusConflict, gin.H{"error": "配對票已完成，無法取消"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "取消配對失敗"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, ticket)
+}
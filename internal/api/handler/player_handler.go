@@ -0,0 +1,113 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nelawu/BagchalGolang/internal/api/middleware"
+	"github.com/nelawu/BagchalGolang/internal/domain/player"
+)
+
+type PlayerHandler struct {
+	playerService *player.PlayerService
+}
+
+func NewPlayerHandler(playerService *player.PlayerService) *PlayerHandler {
+	return &PlayerHandler{
+		playerService: playerService,
+	}
+}
+
+// RegisterRoutes 註冊路由
+func (h *PlayerHandler) RegisterRoutes(router *gin.Engine, requireAuth gin.HandlerFunc) {
+	playerGroup := router.Group("/api/players")
+	{
+		playerGroup.POST("", h.register)
+		playerGroup.GET("/:id", h.getPlayer)
+		playerGroup.PUT("/:id", requireAuth, h.updateProfile)
+	}
+}
+
+// RegisterPlayerRequest 註冊玩家請求
+type RegisterPlayerRequest struct {
+	Username    string `json:"username" binding:"required"`
+	DisplayName string `json:"displayName"`
+}
+
+// register 註冊新玩家
+func (h *PlayerHandler) register(c *gin.Context) {
+	var req RegisterPlayerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "無效的請求數據"})
+		return
+	}
+
+	newPlayer, apiKey, err := h.playerService.Register(req.Username, req.DisplayName)
+	if err != nil {
+		switch err {
+		case player.ErrInvalidUsername:
+			c.JSON(http.StatusBadRequest, gin.H{"error": "無效的用戶名（應為3-20位字母、數字或底線）"})
+		case player.ErrInvalidDisplayName:
+			c.JSON(http.StatusBadRequest, gin.H{"error": "顯示名稱過長"})
+		case player.ErrUsernameTaken:
+			c.JSON(http.StatusConflict, gin.H{"error": "用戶名已被使用"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "註冊玩家失敗"})
+		}
+		return
+	}
+
+	// API密鑰只在註冊時返回一次，之後用於換取會話令牌
+	c.JSON(http.StatusCreated, gin.H{
+		"player": newPlayer,
+		"apiKey": apiKey,
+	})
+}
+
+// getPlayer 獲取玩家資料
+func (h *PlayerHandler) getPlayer(c *gin.Context) {
+	p, err := h.playerService.GetPlayer(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "玩家不存在"})
+		return
+	}
+
+	c.JSON(http.StatusOK, p)
+}
+
+// UpdateProfileRequest 更新玩家資料請求
+type UpdateProfileRequest struct {
+	DisplayName string             `json:"displayName"`
+	Preferences player.Preferences `json:"preferences"`
+}
+
+// updateProfile 更新顯示名稱與偏好設置
+func (h *PlayerHandler) updateProfile(c *gin.Context) {
+	var req UpdateProfileRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "無效的請求數據"})
+		return
+	}
+
+	if c.Param("id") != middleware.PlayerID(c) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "無權修改其他玩家的資料"})
+		return
+	}
+
+	updated, err := h.playerService.UpdateProfile(c.Param("id"), req.DisplayName, req.Preferences)
+	if err != nil {
+		switch err {
+		case player.ErrPlayerNotFound:
+			c.JSON(http.StatusNotFound, gin.H{"error": "玩家不存在"})
+		case player.ErrInvalidDisplayName:
+			c.JSON(http.StatusBadRequest, gin.H{"error": "顯示名稱過長"})
+		case player.ErrInvalidPreferences:
+			c.JSON(http.StatusBadRequest, gin.H{"error": "無效的偏好設置"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "更新玩家資料失敗"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, updated)
+}
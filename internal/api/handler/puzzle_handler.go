@@ -0,0 +1,118 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nelawu/BagchalGolang/internal/api/middleware"
+	"github.com/nelawu/BagchalGolang/internal/domain/game"
+	"github.com/nelawu/BagchalGolang/internal/domain/puzzle"
+)
+
+type PuzzleHandler struct {
+	puzzleService *puzzle.PuzzleService
+}
+
+func NewPuzzleHandler(puzzleService *puzzle.PuzzleService) *PuzzleHandler {
+	return &PuzzleHandler{
+		puzzleService: puzzleService,
+	}
+}
+
+// RegisterRoutes 註冊路由，作答及生成謎題需身份驗證
+func (h *PuzzleHandler) RegisterRoutes(router *gin.Engine, requireAuth gin.HandlerFunc) {
+	puzzleGroup := router.Group("/api/puzzles")
+	{
+		puzzleGroup.GET("", h.listPuzzles)
+		puzzleGroup.GET("/:id", h.getPuzzle)
+		puzzleGroup.POST("/:id/attempts", requireAuth, h.attempt)
+		puzzleGroup.POST("/generate", requireAuth, h.generate)
+	}
+}
+
+// listPuzzles 列出謎題，可按主題及難度篩選
+func (h *PuzzleHandler) listPuzzles(c *gin.Context) {
+	minRating, _ := strconv.ParseFloat(c.Query("minRating"), 64)
+	maxRating, _ := strconv.ParseFloat(c.Query("maxRating"), 64)
+	limit, _ := strconv.Atoi(c.Query("limit"))
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+
+	puzzles, err := h.puzzleService.ListPuzzles(puzzle.Filter{
+		Theme:     puzzle.Theme(c.Query("theme")),
+		MinRating: minRating,
+		MaxRating: maxRating,
+		Limit:     limit,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "獲取謎題失敗"})
+		return
+	}
+
+	c.JSON(http.StatusOK, puzzles)
+}
+
+// getPuzzle 獲取謎題，不包含答案
+func (h *PuzzleHandler) getPuzzle(c *gin.Context) {
+	p, err := h.puzzleService.GetPuzzle(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "謎題不存在"})
+		return
+	}
+
+	c.JSON(http.StatusOK, p)
+}
+
+// attempt 提交答案
+func (h *PuzzleHandler) attempt(c *gin.Context) {
+	var move game.Move
+	if err := c.ShouldBindJSON(&move); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "無效的移動數據"})
+		return
+	}
+
+	result, err := h.puzzleService.Attempt(c.Param("id"), middleware.PlayerID(c), move)
+	if err != nil {
+		switch err {
+		case puzzle.ErrPuzzleNotFound:
+			c.JSON(http.StatusNotFound, gin.H{"error": "謎題不存在"})
+		case puzzle.ErrInvalidMove:
+			c.JSON(http.StatusBadRequest, gin.H{"error": "無效的移動"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "提交答案失敗"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// GeneratePuzzlesRequest 生成謎題請求
+type GeneratePuzzlesRequest struct {
+	SelfPlayGames int `json:"selfPlayGames" binding:"required"`
+}
+
+// generate 以引擎自我對弈在背景生成謎題
+func (h *PuzzleHandler) generate(c *gin.Context) {
+	var req GeneratePuzzlesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "無效的請求數據"})
+		return
+	}
+
+	if err := h.puzzleService.RequestSelfPlay(req.SelfPlayGames); err != nil {
+		switch err {
+		case puzzle.ErrInvalidSelfPlay:
+			c.JSON(http.StatusBadRequest, gin.H{"error": "自我對弈局數必須在1-20之間"})
+		case puzzle.ErrQueueFull:
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": "挖掘隊列已滿，請稍後再試"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "生成謎題失敗"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"message": "已加入挖掘隊列"})
+}
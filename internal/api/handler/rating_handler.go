@@ -0,0 +1,40 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nelawu/BagchalGolang/internal/domain/game"
+	"github.com/nelawu/BagchalGolang/internal/domain/rating"
+)
+
+type RatingHandler struct {
+	ratingService *rating.Service
+}
+
+func NewRatingHandler(ratingService *rating.Service) *RatingHandler {
+	return &RatingHandler{
+		ratingService: ratingService,
+	}
+}
+
+// RegisterRoutes 註冊路由
+func (h *RatingHandler) RegisterRoutes(router *gin.Engine) {
+	router.GET("/api/players/:id/rating", h.getRating)
+}
+
+// getRating 獲取玩家積分及積分歷史
+func (h *RatingHandler) getRating(c *gin.Context) {
+	playerRating, err := h.ratingService.GetPlayerRating(c.Param("id"))
+	if err != nil {
+		switch err {
+		case game.ErrPlayerNotFound:
+			c.JSON(http.StatusNotFound, gin.H{"error": "玩家不存在"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "獲取積分失敗"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, playerRating)
+}
@@ -0,0 +1,115 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nelawu/BagchalGolang/internal/api/middleware"
+	"github.com/nelawu/BagchalGolang/internal/domain/game"
+	"github.com/nelawu/BagchalGolang/internal/domain/rematch"
+)
+
+type RematchHandler struct {
+	rematchService *rematch.RematchService
+}
+
+func NewRematchHandler(rematchService *rematch.RematchService) *RematchHandler {
+	return &RematchHandler{
+		rematchService: rematchService,
+	}
+}
+
+// RegisterRoutes 註冊路由，發起、接受及拒絕再戰需身份驗證
+func (h *RematchHandler) RegisterRoutes(router *gin.Engine, requireAuth gin.HandlerFunc) {
+	router.GET("/api/games/:id/rematch", h.getOffer)
+	router.GET("/api/series/:id", h.getSeries)
+
+	gameGroup := router.Group("/api/games", requireAuth)
+	{
+		gameGroup.POST("/:id/rematch", h.offer)
+		gameGroup.POST("/:id/rematch/accept", h.accept)
+		gameGroup.DELETE("/:id/rematch", h.decline)
+	}
+}
+
+// offer 邀請對手再戰，對手為AI或已邀請時直接創建新遊戲
+func (h *RematchHandler) offer(c *gin.Context) {
+	offer, err := h.rematchService.Offer(c.Param("id"), middleware.PlayerID(c))
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	status := http.StatusAccepted
+	if offer.Status == rematch.OfferAccepted {
+		status = http.StatusCreated
+	}
+	c.JSON(status, offer)
+}
+
+// accept 接受再戰邀請
+func (h *RematchHandler) accept(c *gin.Context) {
+	offer, err := h.rematchService.Accept(c.Param("id"), middleware.PlayerID(c))
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, offer)
+}
+
+// decline 拒絕或撤回再戰邀請
+func (h *RematchHandler) decline(c *gin.Context) {
+	if err := h.rematchService.Decline(c.Param("id"), middleware.PlayerID(c)); err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "再戰邀請已取消"})
+}
+
+// getOffer 獲取遊戲的再戰邀請
+func (h *RematchHandler) getOffer(c *gin.Context) {
+	offer, err := h.rematchService.GetOffer(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "沒有再戰邀請"})
+		return
+	}
+
+	c.JSON(http.StatusOK, offer)
+}
+
+// getSeries 獲取再戰系列比分
+func (h *RematchHandler) getSeries(c *gin.Context) {
+	series, err := h.rematchService.GetSeries(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "系列不存在"})
+		return
+	}
+
+	c.JSON(http.StatusOK, series)
+}
+
+// writeError 將再戰相關錯誤轉換為HTTP回應
+func (h *RematchHandler) writeError(c *gin.Context, err error) {
+	switch err {
+	case game.ErrGameNotFound:
+		c.JSON(http.StatusNotFound, gin.H{"error": "遊戲不存在"})
+	case rematch.ErrOfferNotFound:
+		c.JSON(http.StatusNotFound, gin.H{"error": "沒有再戰邀請"})
+	case game.ErrForbidden:
+		c.JSON(http.StatusForbidden, gin.H{"error": "只有遊戲參與者可以再戰"})
+	case rematch.ErrNotOfferRecipient:
+		c.JSON(http.StatusForbidden, gin.H{"error": "只有受邀玩家可以接受再戰"})
+	case game.ErrGameNotOver:
+		c.JSON(http.StatusConflict, gin.H{"error": "遊戲尚未結束"})
+	case rematch.ErrAlreadyRematched:
+		c.JSON(http.StatusConflict, gin.H{"error": "此遊戲已經再戰過"})
+	case rematch.ErrAlreadyOffered:
+		c.JSON(http.StatusConflict, gin.H{"error": "已發出再戰邀請"})
+	case rematch.ErrNoOpponent:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "此遊戲沒有對手"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "再戰失敗"})
+	}
+}
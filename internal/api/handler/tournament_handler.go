@@ -0,0 +1,123 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nelawu/BagchalGolang/internal/api/middleware"
+	"github.com/nelawu/BagchalGolang/internal/domain/game"
+	"github.com/nelawu/BagchalGolang/internal/domain/tournament"
+)
+
+type TournamentHandler struct {
+	tournamentService *tournament.TournamentService
+}
+
+func NewTournamentHandler(tournamentService *tournament.TournamentService) *TournamentHandler {
+	return &TournamentHandler{
+		tournamentService: tournamentService,
+	}
+}
+
+// RegisterRoutes 註冊路由
+func (h *TournamentHandler) RegisterRoutes(router *gin.Engine, requireAuth gin.HandlerFunc) {
+	tournamentGroup := router.Group("/api/tournaments")
+	{
+		tournamentGroup.POST("", requireAuth, h.createTournament)
+		tournamentGroup.GET("/:id", h.getTournament)
+		tournamentGroup.GET("/:id/standings", h.getStandings)
+		tournamentGroup.POST("/:id/start", requireAuth, h.startTournament)
+	}
+}
+
+// ParticipantRequest 參賽者，playerId 與 aiLevel 二選一
+type ParticipantRequest struct {
+	PlayerID string `json:"playerId"`
+	AILevel  int    `json:"aiLevel"`
+}
+
+// CreateTournamentRequest 創建賽事請求
+type CreateTournamentRequest struct {
+	Name         string                   `json:"name" binding:"required"`
+	System       tournament.PairingSystem `json:"system" binding:"required"` // round_robin 或 swiss
+	Rounds       int                      `json:"rounds"`                    // 瑞士制輪數，0 表示預設
+	RuleSet      tournament.RuleSet       `json:"ruleSet"`
+	Participants []ParticipantRequest     `json:"participants" binding:"required"`
+}
+
+// createTournament 創建賽事，創建者為主辦者
+func (h *TournamentHandler) createTournament(c *gin.Context) {
+	var req CreateTournamentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "無效的請求數據"})
+		return
+	}
+
+	participants := make([]tournament.Participant, 0, len(req.Participants))
+	for _, p := range req.Participants {
+		if (p.PlayerID == "") == (p.AILevel == 0) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "參賽者需指定 playerId 或 aiLevel 其中之一"})
+			return
+		}
+		participants = append(participants, tournament.Participant{ID: p.PlayerID, AILevel: p.AILevel})
+	}
+
+	newTournament, err := h.tournamentService.CreateTournament(middleware.PlayerID(c), req.Name, req.System, participants, req.RuleSet, req.Rounds)
+	if err != nil {
+		switch err {
+		case tournament.ErrInvalidTournament:
+			c.JSON(http.StatusBadRequest, gin.H{"error": "無效的賽事設置"})
+		case tournament.ErrDuplicateParticipant:
+			c.JSON(http.StatusBadRequest, gin.H{"error": "參賽者重複"})
+		case game.ErrPlayerNotFound:
+			c.JSON(http.StatusNotFound, gin.H{"error": "參賽玩家不存在"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "創建賽事失敗"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, newTournament)
+}
+
+// getTournament 獲取賽事，包含各輪對陣與結果
+func (h *TournamentHandler) getTournament(c *gin.Context) {
+	t, err := h.tournamentService.GetTournament(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "賽事不存在"})
+		return
+	}
+
+	c.JSON(http.StatusOK, t)
+}
+
+// getStandings 獲取賽事積分榜
+func (h *TournamentHandler) getStandings(c *gin.Context) {
+	standings, err := h.tournamentService.GetStandings(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "賽事不存在"})
+		return
+	}
+
+	c.JSON(http.StatusOK, standings)
+}
+
+// startTournament 開始賽事並編排第一輪
+func (h *TournamentHandler) startTournament(c *gin.Context) {
+	t, err := h.tournamentService.StartTournament(c.Param("id"), middleware.PlayerID(c))
+	if err != nil {
+		switch err {
+		case tournament.ErrTournamentNotFound:
+			c.JSON(http.StatusNotFound, gin.H{"error": "賽事不存在"})
+		case tournament.ErrNotOrganizer:
+			c.JSON(http.StatusForbidden, gin.H{"error": "只有主辦者可以開始賽事"})
+		case tournament.ErrAlreadyStarted:
+			c.JSON(http.StatusConflict, gin.H{"error": "賽事已開始"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "開始賽事失敗"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, t)
+}
@@ -0,0 +1,41 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nelawu/BagchalGolang/internal/auth"
+)
+
+// playerIDKey 已驗證玩家ID在請求上下文中的鍵
+const playerIDKey = "authPlayerID"
+
+// RequireAuth 驗證 Authorization: Bearer <token> 並將玩家ID寫入請求上下文
+// 瀏覽器的 EventSource 無法設置請求頭，因此也接受 access_token 查詢參數
+func RequireAuth(issuer *auth.TokenIssuer) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token, found := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if !found {
+			token = c.Query("access_token")
+		}
+		if token == "" {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "未提供身份驗證令牌"})
+			return
+		}
+
+		claims, err := issuer.Verify(token)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "身份驗證令牌無效或已過期"})
+			return
+		}
+
+		c.Set(playerIDKey, claims.PlayerID)
+		c.Next()
+	}
+}
+
+// PlayerID 返回已驗證的玩家ID，未經 RequireAuth 的請求返回空字串
+func PlayerID(c *gin.Context) string {
+	return c.GetString(playerIDKey)
+}
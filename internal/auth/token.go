@@ -0,0 +1,94 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalidToken = errors.New("invalid token")
+	ErrTokenExpired = errors.New("token expired")
+)
+
+// Claims 表示會話令牌中攜帶的資訊
+type Claims struct {
+	PlayerID  string `json:"sub"`
+	IssuedAt  int64  `json:"iat"`
+	ExpiresAt int64  `json:"exp"`
+}
+
+// TokenIssuer 簽發並在本地驗證 HMAC-SHA256 簽名的會話令牌
+type TokenIssuer struct {
+	secret []byte
+	ttl    time.Duration
+}
+
+func NewTokenIssuer(secret []byte, ttl time.Duration) *TokenIssuer {
+	return &TokenIssuer{
+		secret: secret,
+		ttl:    ttl,
+	}
+}
+
+// NewRandomSecret 生成隨機簽名密鑰，服務重啟後舊令牌將失效
+func NewRandomSecret() ([]byte, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, err
+	}
+	return secret, nil
+}
+
+// Issue 為玩家簽發會話令牌
+func (t *TokenIssuer) Issue(playerID string) (string, time.Time, error) {
+	now := time.Now()
+	expiresAt := now.Add(t.ttl)
+	payload, err := json.Marshal(Claims{
+		PlayerID:  playerID,
+		IssuedAt:  now.Unix(),
+		ExpiresAt: expiresAt.Unix(),
+	})
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	return encoded + "." + t.sign(encoded), expiresAt, nil
+}
+
+// Verify 驗證令牌簽名與有效期
+func (t *TokenIssuer) Verify(token string) (*Claims, error) {
+	encoded, signature, found := strings.Cut(token, ".")
+	if !found {
+		return nil, ErrInvalidToken
+	}
+	if !hmac.Equal([]byte(signature), []byte(t.sign(encoded))) {
+		return nil, ErrInvalidToken
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+	var claims Claims
+	if err := json.Unmarshal(payload, &claims); err != nil || claims.PlayerID == "" {
+		return nil, ErrInvalidToken
+	}
+	if time.Now().Unix() >= claims.ExpiresAt {
+		return nil, ErrTokenExpired
+	}
+	return &claims, nil
+}
+
+// sign 計算簽名
+func (t *TokenIssuer) sign(encoded string) string {
+	mac := hmac.New(sha256.New, t.secret)
+	mac.Write([]byte(encoded))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,203 @@
+package analysis
+
+import (
+	"log"
+	"math"
+	"time"
+
+	"github.com/nelawu/BagchalGolang/internal/ai"
+	"github.com/nelawu/BagchalGolang/internal/domain/game"
+)
+
+// 賽後註解參數
+const (
+	annotationDepth    = 3
+	annotationMoveTime = 300 * time.Millisecond
+
+	// 評估損失達到以下門檻（單位：百分之一隻羊）即歸入對應類別
+	inaccuracyThreshold = 50
+	mistakeThreshold    = 100
+	blunderThreshold    = 200
+
+	// scoreCap 計算損失與準確度前將勝負分數截斷到此範圍
+	scoreCap = 1000
+
+	// annotationQueueSize 等待註解的遊戲數上限，隊列已滿時放棄自動註解
+	annotationQueueSize = 64
+)
+
+// Start 啟動背景註解循環，逐一處理已結束的遊戲
+func (s *AnalysisService) Start() {
+	go func() {
+		for {
+			select {
+			case gameID := <-s.jobs:
+				if _, err := s.Annotate(gameID); err != nil {
+					log.Printf("註解遊戲 %s 失敗: %v", gameID, err)
+				}
+			case <-s.stop:
+				return
+			}
+		}
+	}()
+}
+
+// Stop 停止背景註解循環
+func (s *AnalysisService) Stop() {
+	s.once.Do(func() { close(s.stop) })
+}
+
+// HandleGameOver 將已結束的遊戲加入註解隊列
+func (s *AnalysisService) HandleGameOver(g *game.Game) {
+	if err := s.enqueue(g.ID); err != nil {
+		log.Printf("註解隊列已滿，跳過遊戲 %s", g.ID)
+	}
+}
+
+// RequestAnnotation 將已結束的遊戲加入註解隊列，完成後註解保存到遊戲
+func (s *AnalysisService) RequestAnnotation(gameID string) error {
+	g, err := s.games.GetGame(gameID)
+	if err != nil {
+		return game.ErrGameNotFound
+	}
+	if !g.State.IsGameOver {
+		return game.ErrGameNotOver
+	}
+	return s.enqueue(gameID)
+}
+
+// GetAnnotation 獲取遊戲的賽後註解
+func (s *AnalysisService) GetAnnotation(gameID string) (*game.Annotation, error) {
+	g, err := s.games.GetGame(gameID)
+	if err != nil {
+		return nil, game.ErrGameNotFound
+	}
+	if g.Annotation == nil {
+		return nil, ErrAnnotationNotReady
+	}
+	return g.Annotation, nil
+}
+
+// enqueue 將遊戲加入註解隊列，不阻塞
+func (s *AnalysisService) enqueue(gameID string) error {
+	select {
+	case s.jobs <- gameID:
+		return nil
+	default:
+		return ErrQueueFull
+	}
+}
+
+// Annotate 對已結束遊戲的每一步進行引擎分析，標記失誤並保存到遊戲
+func (s *AnalysisService) Annotate(gameID string) (*game.Annotation, error) {
+	g, err := s.games.GetGame(gameID)
+	if err != nil {
+		return nil, game.ErrGameNotFound
+	}
+	if !g.State.IsGameOver {
+		return nil, game.ErrGameNotOver
+	}
+	history := append([]game.Move(nil), g.History...)
+
+	// 分析每個局面，results[i] 為第 i 步之後的局面
+	limits := ai.Limits{Depth: annotationDepth, MoveTime: annotationMoveTime}
+	results := make([]*ai.Analysis, len(history)+1)
+	state := game.NewGameState()
+	for i := 0; ; i++ {
+		results[i] = s.engine.Analyze(state, limits)
+		if i == len(history) {
+			break
+		}
+		game.ApplyMove(&state, history[i])
+	}
+
+	annotation := &game.Annotation{
+		Moves:     make([]game.MoveAnnotation, 0, len(history)),
+		Depth:     annotationDepth,
+		CreatedAt: time.Now(),
+	}
+	accuracy := map[game.PieceType][]float64{}
+	for i, move := range history {
+		before := sideScore(results[i], move.PieceType)
+		after := sideScore(results[i+1], move.PieceType)
+		loss := before - after
+		if loss < 0 {
+			loss = 0
+		}
+		if best := results[i].BestMove; best != nil && sameMove(*best, move) {
+			loss = 0
+		}
+
+		annotation.Moves = append(annotation.Moves, game.MoveAnnotation{
+			Ply:         i + 1,
+			Move:        move,
+			Side:        move.PieceType,
+			BestMove:    results[i].BestMove,
+			ScoreBefore: before,
+			ScoreAfter:  after,
+			Loss:        loss,
+			Class:       classify(loss),
+		})
+		accuracy[move.PieceType] = append(accuracy[move.PieceType], moveAccuracy(before, before-loss))
+	}
+	annotation.TigerAccuracy = mean(accuracy[game.Tiger])
+	annotation.GoatAccuracy = mean(accuracy[game.Goat])
+
+	if err := s.games.SetAnnotation(gameID, annotation); err != nil {
+		return nil, err
+	}
+	return annotation, nil
+}
+
+// sideScore 返回分析結果在指定陣營視角下的分數，並截斷勝負分數
+func sideScore(result *ai.Analysis, side game.PieceType) int {
+	score := result.TigerScore
+	if side == game.Goat {
+		score = result.GoatScore
+	}
+	return max(-scoreCap, min(scoreCap, score))
+}
+
+// sameMove 檢查兩步棋的起點、終點與棋子是否相同
+func sameMove(a, b game.Move) bool {
+	return a.From == b.From && a.To == b.To && a.PieceType == b.PieceType
+}
+
+// classify 根據評估損失評價一步棋
+func classify(loss int) game.MoveClass {
+	switch {
+	case loss >= blunderThreshold:
+		return game.MoveBlunder
+	case loss >= mistakeThreshold:
+		return game.MoveMistake
+	case loss >= inaccuracyThreshold:
+		return game.MoveInaccuracy
+	case loss > 0:
+		return game.MoveGood
+	}
+	return game.MoveBest
+}
+
+// winPercent 將評估分數轉換為勝率（0-100）
+func winPercent(score int) float64 {
+	return 100 / (1 + math.Exp(-0.004*float64(score)))
+}
+
+// moveAccuracy 根據走棋前後的勝率差計算單步準確度（0-100）
+func moveAccuracy(before, after int) float64 {
+	drop := winPercent(before) - winPercent(after)
+	accuracy := 103.1668*math.Exp(-0.04354*drop) - 3.1669
+	return max(0, min(100, accuracy))
+}
+
+// mean 返回平均值，沒有數據時返回 0
+func mean(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	total := 0.0
+	for _, v := range values {
+		total += v
+	}
+	return math.Round(total/float64(len(values))*10) / 10
+}
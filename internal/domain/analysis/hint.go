@@ -0,0 +1,101 @@
+package analysis
+
+import (
+	"errors"
+	"time"
+
+	"github.com/nelawu/BagchalGolang/internal/ai"
+	"github.com/nelawu/BagchalGolang/internal/domain/game"
+)
+
+var ErrInvalidStrength = errors.New("invalid hint strength")
+
+// HintReason 表示提示移動的理由類別
+type HintReason string
+
+const (
+	ReasonCaptures         HintReason = "captures"          // 吃掉一隻羊
+	ReasonThreatensCapture HintReason = "threatens_capture" // 製造吃子威脅
+	ReasonBlocksCapture    HintReason = "blocks_capture"    // 擋住或避開虎的吃子
+	ReasonTrapsTiger       HintReason = "traps_tiger"       // 困住一隻虎
+	ReasonPositional       HintReason = "positional"        // 改善局面
+)
+
+// hintDepths 各提示強度對應的搜索深度
+var hintDepths = map[int]int{1: 1, 2: 2, 3: 4}
+
+// DefaultHintStrength 未指定強度時使用的提示強度
+const DefaultHintStrength = 2
+
+// hintMoveTime 提示搜索的時間上限
+const hintMoveTime = time.Second
+
+// Hint 表示給學習者的提示
+type Hint struct {
+	Move      game.Move  `json:"move"`
+	Reason    HintReason `json:"reason"`
+	Strength  int        `json:"strength"`
+	HintsUsed int        `json:"hintsUsed"` // 本局已使用的提示次數
+}
+
+// Hint 為輪到的玩家提供建議移動，使用提示後該遊戲不再計分
+func (s *AnalysisService) Hint(gameID, playerID string, strength int) (*Hint, error) {
+	if strength == 0 {
+		strength = DefaultHintStrength
+	}
+	depth, ok := hintDepths[strength]
+	if !ok {
+		return nil, ErrInvalidStrength
+	}
+
+	g, err := s.games.GetGame(gameID)
+	if err != nil {
+		return nil, game.ErrGameNotFound
+	}
+	if !g.HasPlayer(playerID) {
+		return nil, game.ErrForbidden
+	}
+	if g.State.IsGameOver {
+		return nil, game.ErrGameOver
+	}
+	if !g.CanMove(playerID, g.State.CurrentTurn) {
+		return nil, game.ErrNotPlayersTurn
+	}
+
+	state := g.State
+	result := s.engine.Analyze(state, ai.Limits{Depth: depth, MoveTime: hintMoveTime})
+	if result.BestMove == nil {
+		return nil, game.ErrGameOver
+	}
+
+	updated, err := s.games.RecordHint(gameID)
+	if err != nil {
+		return nil, err
+	}
+	return &Hint{
+		Move:      *result.BestMove,
+		Reason:    ClassifyMove(&state, *result.BestMove),
+		Strength:  strength,
+		HintsUsed: updated.HintsUsed,
+	}, nil
+}
+
+// ClassifyMove 比較移動前後的局面特徵，判斷移動的主要理由
+func ClassifyMove(state *game.GameState, move game.Move) HintReason {
+	before := ai.ExtractFeatures(state)
+	next := *state
+	game.ApplyMove(&next, move)
+	after := ai.ExtractFeatures(&next)
+
+	switch {
+	case move.Capture != nil:
+		return ReasonCaptures
+	case move.PieceType == game.Goat && after.TrappedTigers > before.TrappedTigers:
+		return ReasonTrapsTiger
+	case move.PieceType == game.Goat && after.GoatsThreatened < before.GoatsThreatened:
+		return ReasonBlocksCapture
+	case move.PieceType == game.Tiger && after.GoatsThreatened > before.GoatsThreatened:
+		return ReasonThreatensCapture
+	}
+	return ReasonPositional
+}
@@ -0,0 +1,149 @@
+package analysis
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/nelawu/BagchalGolang/internal/ai"
+	"github.com/nelawu/BagchalGolang/internal/domain/game"
+)
+
+var (
+	ErrInvalidPosition    = errors.New("invalid position")
+	ErrAnnotationNotReady = errors.New("annotation not ready")
+	ErrQueueFull          = errors.New("annotation queue is full")
+)
+
+// 分析的時間限制
+const (
+	DefaultMoveTime = 2 * time.Second
+	MaxMoveTime     = 10 * time.Second
+)
+
+// Analyzer 搜索及評估局面的引擎，由 ai.Engine 實現
+type Analyzer interface {
+	Analyze(state game.GameState, limits ai.Limits) *ai.Analysis
+	Explain(state game.GameState) *ai.Explanation
+}
+
+// GameSource 提供遊戲查詢、保存註解及記錄提示，由 game.GameService 實現
+type GameSource interface {
+	GetGame(id string) (*game.Game, error)
+	ListPlayerGames(playerID string) ([]*game.Game, error)
+	SetAnnotation(gameID string, annotation *game.Annotation) error
+	RecordHint(gameID string) (*game.Game, error)
+}
+
+type AnalysisService struct {
+	engine Analyzer
+	games  GameSource
+
+	jobs chan string // 等待註解的遊戲ID
+	stop chan struct{}
+	once sync.Once
+}
+
+func NewAnalysisService(engine Analyzer, games GameSource) *AnalysisService {
+	return &AnalysisService{
+		engine: engine,
+		games:  games,
+		jobs:   make(chan string, annotationQueueSize),
+		stop:   make(chan struct{}),
+	}
+}
+
+// AnalyzePosition 代表玩家分析任意局面，時間預算為 0 時使用預設值
+// 局面與玩家進行中的遊戲的當前局面相同（含對稱變換）時拒絕，進行中的遊戲只能使用會取消計分的提示
+func (s *AnalysisService) AnalyzePosition(playerID string, state game.GameState, depth int, moveTime time.Duration) (*ai.Analysis, error) {
+	if err := normalize(&state); err != nil {
+		return nil, err
+	}
+
+	games, err := s.games.ListPlayerGames(playerID)
+	if err != nil {
+		return nil, err
+	}
+	key, _ := game.CanonicalKey(&state)
+	for _, g := range games {
+		if g.State.IsGameOver {
+			continue
+		}
+		if current, _ := game.CanonicalKey(&g.State); current == key {
+			return nil, game.ErrGameNotOver
+		}
+	}
+	return s.engine.Analyze(state, limits(depth, moveTime)), nil
+}
+
+// ExplainPosition 分解局面的靜態評估，列出每個特徵的值、權重及貢獻
+func (s *AnalysisService) ExplainPosition(state game.GameState) (*ai.Explanation, error) {
+	if err := normalize(&state); err != nil {
+		return nil, err
+	}
+	return s.engine.Explain(state), nil
+}
+
+// AnalyzeGame 代表玩家分析遊戲第 ply 步之後的局面，ply 為負數時分析當前局面
+// 參與者在遊戲結束前不能分析，否則可繞過提示取消計分的規則
+func (s *AnalysisService) AnalyzeGame(gameID, playerID string, ply, depth int, moveTime time.Duration) (*ai.Analysis, error) {
+	g, err := s.games.GetGame(gameID)
+	if err != nil {
+		return nil, game.ErrGameNotFound
+	}
+	if !g.State.IsGameOver && g.HasPlayer(playerID) {
+		return nil, game.ErrGameNotOver
+	}
+	if ply < 0 {
+		ply = len(g.History)
+	}
+
+	state, err := g.StateAtPly(ply)
+	if err != nil {
+		return nil, err
+	}
+	return s.engine.Analyze(state, limits(depth, moveTime)), nil
+}
+
+// limits 將請求參數限制在允許範圍內
+func limits(depth int, moveTime time.Duration) ai.Limits {
+	if moveTime <= 0 {
+		moveTime = DefaultMoveTime
+	}
+	if moveTime > MaxMoveTime {
+		moveTime = MaxMoveTime
+	}
+	return ai.Limits{Depth: depth, MoveTime: moveTime}
+}
+
+// normalize 驗證客戶端提交的局面並重新判定勝負
+func normalize(state *game.GameState) error {
+	if state.CurrentTurn != game.Tiger && state.CurrentTurn != game.Goat {
+		return ErrInvalidPosition
+	}
+	if state.GoatsInHand < 0 || state.GoatsInHand > game.MaxGoats || state.CapturedGoats < 0 {
+		return ErrInvalidPosition
+	}
+
+	tigers, goats := 0, 0
+	for _, row := range state.Board {
+		for _, piece := range row {
+			switch piece {
+			case game.Tiger:
+				tigers++
+			case game.Goat:
+				goats++
+			case game.Empty:
+			default:
+				return ErrInvalidPosition
+			}
+		}
+	}
+	if tigers != game.MaxTigers || goats+state.GoatsInHand+state.CapturedGoats != game.MaxGoats {
+		return ErrInvalidPosition
+	}
+
+	state.LastMove = nil
+	state.IsGameOver, state.Winner = game.Outcome(state)
+	return nil
+}
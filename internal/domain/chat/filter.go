@@ -0,0 +1,165 @@
+package chat
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+var (
+	ErrMessageRejected = errors.New("message rejected by filter")
+	ErrRateLimited     = errors.New("too many messages")
+)
+
+// Filter 聊天訊息過濾器，可改寫訊息內容或返回錯誤拒絕訊息
+type Filter interface {
+	Filter(message *Message) error
+}
+
+// FilterFunc 將函數適配為 Filter
+type FilterFunc func(message *Message) error
+
+func (f FilterFunc) Filter(message *Message) error {
+	return f(message)
+}
+
+// WordFilter 將屏蔽詞替換為星號，不區分大小寫
+type WordFilter struct {
+	words []string
+}
+
+func NewWordFilter(words []string) *WordFilter {
+	lowered := make([]string, 0, len(words))
+	for _, word := range words {
+		if word = strings.TrimSpace(word); word != "" {
+			lowered = append(lowered, strings.ToLower(word))
+		}
+	}
+	return &WordFilter{words: lowered}
+}
+
+func (f *WordFilter) Filter(message *Message) error {
+	for _, word := range f.words {
+		message.Text = replaceFold(message.Text, word, strings.Repeat("*", utf8.RuneCountInString(word)))
+	}
+	return nil
+}
+
+// replaceFold 不區分大小寫地替換所有出現的子字串
+func replaceFold(text, word, replacement string) string {
+	lower := strings.ToLower(text)
+	// 大小寫轉換改變位元組長度時無法對齊位置，退回區分大小寫的替換
+	if len(lower) != len(text) {
+		return strings.ReplaceAll(text, word, replacement)
+	}
+
+	var builder strings.Builder
+	for {
+		index := strings.Index(lower, word)
+		if index < 0 {
+			builder.WriteString(text)
+			return builder.String()
+		}
+		builder.WriteString(text[:index])
+		builder.WriteString(replacement)
+		text, lower = text[index+len(word):], lower[index+len(word):]
+	}
+}
+
+// RateLimitFilter 限制每位玩家在時間窗口內的訊息數量，用於防止刷屏
+type RateLimitFilter struct {
+	limit  int
+	window time.Duration
+
+	mu        sync.Mutex
+	sent      map[string][]time.Time
+	lastSweep time.Time
+}
+
+func NewRateLimitFilter(limit int, window time.Duration) *RateLimitFilter {
+	return &RateLimitFilter{
+		limit:  limit,
+		window: window,
+		sent:   make(map[string][]time.Time),
+	}
+}
+
+func (f *RateLimitFilter) Filter(message *Message) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	cutoff := message.CreatedAt.Add(-f.window)
+	f.sweepLocked(message.CreatedAt, cutoff)
+
+	recent := f.sent[message.PlayerID][:0]
+	for _, sentAt := range f.sent[message.PlayerID] {
+		if sentAt.After(cutoff) {
+			recent = append(recent, sentAt)
+		}
+	}
+	if len(recent) >= f.limit {
+		f.sent[message.PlayerID] = recent
+		return ErrRateLimited
+	}
+	f.sent[message.PlayerID] = append(recent, message.CreatedAt)
+	return nil
+}
+
+// sweepLocked 每個時間窗口清理一次已不再發言的玩家，調用前需持有鎖
+func (f *RateLimitFilter) sweepLocked(now, cutoff time.Time) {
+	if now.Sub(f.lastSweep) < f.window {
+		return
+	}
+	f.lastSweep = now
+	for playerID, sent := range f.sent {
+		if len(sent) == 0 || !sent[len(sent)-1].After(cutoff) {
+			delete(f.sent, playerID)
+		}
+	}
+}
+
+// RepeatFilter 拒絕在時間窗口內與同一玩家上一條訊息完全相同的訊息
+type RepeatFilter struct {
+	window time.Duration
+
+	mu        sync.Mutex
+	last      map[string]lastMessage
+	lastSweep time.Time
+}
+
+// lastMessage 玩家在一局遊戲中的上一條訊息
+type lastMessage struct {
+	text   string
+	sentAt time.Time
+}
+
+func NewRepeatFilter(window time.Duration) *RepeatFilter {
+	return &RepeatFilter{
+		window: window,
+		last:   make(map[string]lastMessage),
+	}
+}
+
+func (f *RepeatFilter) Filter(message *Message) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	cutoff := message.CreatedAt.Add(-f.window)
+	if message.CreatedAt.Sub(f.lastSweep) >= f.window {
+		f.lastSweep = message.CreatedAt
+		for key, last := range f.last {
+			if !last.sentAt.After(cutoff) {
+				delete(f.last, key)
+			}
+		}
+	}
+
+	key := message.GameID + "/" + message.PlayerID
+	if last, exists := f.last[key]; exists && last.text == message.Text && last.sentAt.After(cutoff) {
+		return ErrMessageRejected
+	}
+	f.last[key] = lastMessage{text: message.Text, sentAt: message.CreatedAt}
+	return nil
+}
@@ -0,0 +1,13 @@
+package chat
+
+import "time"
+
+// Message 表示一條遊戲內聊天訊息
+type Message struct {
+	ID          string    `json:"id"` // 由存儲在保存時分配，同一遊戲內按保存順序遞增
+	GameID      string    `json:"gameId"`
+	PlayerID    string    `json:"playerId"`
+	Text        string    `json:"text"`
+	IsSpectator bool      `json:"isSpectator"` // 發送者是否為觀戰者
+	CreatedAt   time.Time `json:"createdAt"`
+}
@@ -0,0 +1,16 @@
+package chat
+
+// ChatRepository 定義聊天資料存儲介面
+type ChatRepository interface {
+	// Save 保存訊息並分配ID，同一遊戲內按ID排序即按保存順序排序
+	Save(message *Message) error
+
+	// ListByGame 按時間順序列出遊戲的訊息，afterID 非空時只返回其後的訊息
+	ListByGame(gameID, afterID string, limit int) ([]*Message, error)
+
+	// SetMuted 設置玩家是否屏蔽另一位玩家
+	SetMuted(playerID, mutedPlayerID string, muted bool) error
+
+	// ListMuted 列出玩家屏蔽的所有玩家
+	ListMuted(playerID string) ([]string, error)
+}
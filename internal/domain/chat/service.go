@@ -0,0 +1,183 @@
+package chat
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/nelawu/BagchalGolang/internal/domain/game"
+)
+
+var (
+	ErrInvalidMessage = errors.New("invalid message")
+	ErrCannotMuteSelf = errors.New("cannot mute yourself")
+)
+
+const (
+	maxMessageLength  = 500
+	defaultListLimit  = 100
+	subscriberBacklog = 16 // 每個訂閱者的緩衝訊息數，超過時丟棄以免阻塞發送者
+)
+
+// GameSource 用於確認聊天所屬的遊戲存在
+type GameSource interface {
+	GetGame(id string) (*game.Game, error)
+}
+
+// subscriber 表示一個即時接收訊息的連線
+type subscriber struct {
+	viewerID string
+	messages chan *Message
+}
+
+type ChatService struct {
+	repository ChatRepository
+	games      GameSource
+	filters    []Filter
+
+	mu          sync.RWMutex
+	subscribers map[string]map[*subscriber]struct{} // 遊戲ID -> 訂閱者
+}
+
+func NewChatService(repository ChatRepository, games GameSource, filters ...Filter) *ChatService {
+	return &ChatService{
+		repository:  repository,
+		games:       games,
+		filters:     filters,
+		subscribers: make(map[string]map[*subscriber]struct{}),
+	}
+}
+
+// PostMessage 發送訊息，依次通過所有過濾器後保存並推送給訂閱者
+func (s *ChatService) PostMessage(gameID, playerID, text string) (*Message, error) {
+	text = strings.TrimSpace(text)
+	if text == "" || utf8.RuneCountInString(text) > maxMessageLength {
+		return nil, ErrInvalidMessage
+	}
+
+	g, err := s.games.GetGame(gameID)
+	if err != nil {
+		return nil, game.ErrGameNotFound
+	}
+
+	message := &Message{
+		GameID:      gameID,
+		PlayerID:    playerID,
+		Text:        text,
+		IsSpectator: !g.HasPlayer(playerID),
+		CreatedAt:   time.Now(),
+	}
+	for _, filter := range s.filters {
+		if err := filter.Filter(message); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.repository.Save(message); err != nil {
+		return nil, err
+	}
+	s.publish(message)
+	return message, nil
+}
+
+// ListMessages 列出遊戲訊息，不包含觀看者屏蔽的玩家的訊息
+func (s *ChatService) ListMessages(gameID, viewerID, afterID string, limit int) ([]*Message, error) {
+	if _, err := s.games.GetGame(gameID); err != nil {
+		return nil, game.ErrGameNotFound
+	}
+	if limit <= 0 || limit > defaultListLimit {
+		limit = defaultListLimit
+	}
+
+	messages, err := s.repository.ListByGame(gameID, afterID, limit)
+	if err != nil {
+		return nil, err
+	}
+	muted, err := s.mutedSet(viewerID)
+	if err != nil {
+		return nil, err
+	}
+
+	visible := make([]*Message, 0, len(messages))
+	for _, message := range messages {
+		if !muted[message.PlayerID] {
+			visible = append(visible, message)
+		}
+	}
+	return visible, nil
+}
+
+// Subscribe 訂閱遊戲的即時訊息，返回的取消函數必須被調用以釋放資源
+func (s *ChatService) Subscribe(gameID, viewerID string) (<-chan *Message, func(), error) {
+	if _, err := s.games.GetGame(gameID); err != nil {
+		return nil, nil, game.ErrGameNotFound
+	}
+
+	sub := &subscriber{
+		viewerID: viewerID,
+		messages: make(chan *Message, subscriberBacklog),
+	}
+
+	s.mu.Lock()
+	if s.subscribers[gameID] == nil {
+		s.subscribers[gameID] = make(map[*subscriber]struct{})
+	}
+	s.subscribers[gameID][sub] = struct{}{}
+	s.mu.Unlock()
+
+	cancel := func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		delete(s.subscribers[gameID], sub)
+		if len(s.subscribers[gameID]) == 0 {
+			delete(s.subscribers, gameID)
+		}
+	}
+	return sub.messages, cancel, nil
+}
+
+// SetMuted 屏蔽或取消屏蔽另一位玩家的訊息
+func (s *ChatService) SetMuted(playerID, mutedPlayerID string, muted bool) error {
+	if playerID == mutedPlayerID {
+		return ErrCannotMuteSelf
+	}
+	return s.repository.SetMuted(playerID, mutedPlayerID, muted)
+}
+
+// ListMuted 列出玩家屏蔽的所有玩家
+func (s *ChatService) ListMuted(playerID string) ([]string, error) {
+	return s.repository.ListMuted(playerID)
+}
+
+// publish 將訊息推送給遊戲的所有訂閱者，跳過屏蔽了發送者的訂閱者
+func (s *ChatService) publish(message *Message) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for sub := range s.subscribers[message.GameID] {
+		muted, err := s.mutedSet(sub.viewerID)
+		if err != nil || muted[message.PlayerID] {
+			continue
+		}
+		select {
+		case sub.messages <- message:
+		default:
+			// 訂閱者處理過慢時丟棄，客戶端可通過歷史訊息補齊
+		}
+	}
+}
+
+// mutedSet 返回玩家屏蔽的玩家集合
+func (s *ChatService) mutedSet(playerID string) (map[string]bool, error) {
+	mutedIDs, err := s.repository.ListMuted(playerID)
+	if err != nil {
+		return nil, err
+	}
+	muted := make(map[string]bool, len(mutedIDs))
+	for _, id := range mutedIDs {
+		muted[id] = true
+	}
+	return muted, nil
+}
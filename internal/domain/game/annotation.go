@@ -0,0 +1,35 @@
+package game
+
+import "time"
+
+// MoveClass 表示一步棋的評價
+type MoveClass string
+
+const (
+	MoveBest       MoveClass = "best"
+	MoveGood       MoveClass = "good"
+	MoveInaccuracy MoveClass = "inaccuracy"
+	MoveMistake    MoveClass = "mistake"
+	MoveBlunder    MoveClass = "blunder"
+)
+
+// MoveAnnotation 表示對一步棋的引擎註解，分數均以走棋一方視角計算
+type MoveAnnotation struct {
+	Ply         int       `json:"ply"` // 從 1 開始的步數
+	Move        Move      `json:"move"`
+	Side        PieceType `json:"side"`
+	BestMove    *Move     `json:"bestMove,omitempty"` // 引擎推薦的移動
+	ScoreBefore int       `json:"scoreBefore"`        // 走棋前最佳著法的評估
+	ScoreAfter  int       `json:"scoreAfter"`         // 實際走棋後的評估
+	Loss        int       `json:"loss"`               // 評估損失，不小於 0
+	Class       MoveClass `json:"class"`
+}
+
+// Annotation 表示整局遊戲的賽後註解
+type Annotation struct {
+	Moves         []MoveAnnotation `json:"moves"`
+	TigerAccuracy float64          `json:"tigerAccuracy"` // 執虎方準確度（0-100）
+	GoatAccuracy  float64          `json:"goatAccuracy"`  // 執羊方準確度（0-100）
+	Depth         int              `json:"depth"`         // 每個局面的搜索深度
+	CreatedAt     time.Time        `json:"createdAt"`
+}
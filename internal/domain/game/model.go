@@ -1,6 +1,13 @@
 package game
 
 import (
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -45,15 +52,32 @@ type GameState struct {
 	LastMove      *Move                          `json:"lastMove"`
 }
 
+// TimeControl 表示時間控制（單位：秒）
+type TimeControl struct {
+	InitialSeconds   int `json:"initialSeconds"`   // 每方初始時間
+	IncrementSeconds int `json:"incrementSeconds"` // 每步加秒
+}
+
 // Game 表示一局遊戲
 type Game struct {
-	ID        string     `json:"id"`
-	State     GameState  `json:"state"`
-	CreatedAt time.Time  `json:"createdAt"`
-	UpdatedAt time.Time  `json:"updatedAt"`
-	PlayerID  string     `json:"playerId"`  // 玩家ID
-	IsAIGame  bool       `json:"isAIGame"`  // 是否是AI對戰
-	AILevel   int        `json:"aiLevel"`   // AI難度等級
+	ID            string       `json:"id"`
+	State         GameState    `json:"state"`
+	CreatedAt     time.Time    `json:"createdAt"`
+	UpdatedAt     time.Time    `json:"updatedAt"`
+	PlayerID      string       `json:"playerId"`                // 玩家ID（創建者）
+	TigerPlayerID string       `json:"tigerPlayerId,omitempty"` // 執虎方玩家ID
+	GoatPlayerID  string       `json:"goatPlayerId,omitempty"`  // 執羊方玩家ID
+	IsAIGame      bool         `json:"isAIGame"`                // 是否是AI對戰
+	AILevel       int          `json:"aiLevel"`                 // AI難度等級
+	TimeControl   *TimeControl `json:"timeControl,omitempty"`   // 時間控制，nil表示不限時
+	Rated         bool         `json:"rated"`                   // 是否計入積分
+	FinishedAt    *time.Time   `json:"finishedAt,omitempty"`    // 遊戲結束時間
+	RematchOf     string       `json:"rematchOf,omitempty"`     // 若為再戰，原遊戲ID
+	SeriesID      string       `json:"seriesId,omitempty"`      // 所屬的再戰系列
+	History       []Move       `json:"history"`                 // 按順序記錄的所有移動
+	Annotation    *Annotation  `json:"annotation,omitempty"`    // 賽後引擎註解
+	HintsUsed     int          `json:"hintsUsed"`               // 使用提示的次數，使用後不計積分
+	Seed          int64        `json:"seed"`                    // AI隨機選擇的種子，相同種子及走法可重現簡單及中等難度AI的整局遊戲；遊戲結束前不公開
 }
 
 // NewGame 創建一個新遊戲
@@ -65,39 +89,197 @@ func NewGame(playerID string, isAIGame bool, aiLevel int) *Game {
 		PlayerID:  playerID,
 		IsAIGame:  isAIGame,
 		AILevel:   aiLevel,
+		History:   []Move{},
+		Seed:      generateSeed(),
 	}
 
-	// 初始化遊戲狀態
-	game.State = GameState{
-		GoatsInHand:   MaxGoats,
-		CapturedGoats: 0,
-		CurrentTurn:   Goat, // 羊先手
-		IsGameOver:    false,
+	// AI對戰中玩家執羊先手
+	if isAIGame {
+		game.GoatPlayerID = playerID
 	}
 
-	// 放置初始虎子
-	game.State.Board[0][0] = Tiger
-	game.State.Board[0][BoardSize-1] = Tiger
-	game.State.Board[BoardSize-1][0] = Tiger
-	game.State.Board[BoardSize-1][BoardSize-1] = Tiger
+	// 初始化遊戲狀態
+	game.State = NewGameState()
+
+	return game
+}
+
+// NewMatchedGame 創建一局雙方陣營已確定的遊戲，玩家ID可以是AI虛擬玩家ID
+func NewMatchedGame(tigerPlayerID, goatPlayerID string, timeControl *TimeControl, rated bool) *Game {
+	game := NewGame(goatPlayerID, false, 0)
+	game.TigerPlayerID = tigerPlayerID
+	game.GoatPlayerID = goatPlayerID
+	game.TimeControl = timeControl
+	game.Rated = rated
+
+	// 有AI參與時記錄AI難度，創建者為人類玩家
+	if level, ok := ParseAIPlayerID(tigerPlayerID); ok {
+		game.IsAIGame, game.AILevel = true, level
+	}
+	if level, ok := ParseAIPlayerID(goatPlayerID); ok {
+		game.IsAIGame, game.AILevel = true, level
+		game.PlayerID = tigerPlayerID
+	}
+	return game
+}
 
+// NewRematchGame 根據已結束的遊戲創建再戰，沿用時間控制與計分設置並交換雙方陣營
+func NewRematchGame(original *Game) *Game {
+	game := NewGame(original.PlayerID, original.IsAIGame, original.AILevel)
+	game.TigerPlayerID = original.GoatPlayerID
+	game.GoatPlayerID = original.TigerPlayerID
+	game.TimeControl = original.TimeControl
+	game.Rated = original.Rated
+	game.RematchOf = original.ID
+	game.SeriesID = original.SeriesID
 	return game
 }
 
+// SideOf 返回玩家所執的陣營，玩家未指定陣營時返回 Empty
+func (g *Game) SideOf(playerID string) PieceType {
+	switch {
+	case playerID == "":
+		return Empty
+	case g.TigerPlayerID == playerID:
+		return Tiger
+	case g.GoatPlayerID == playerID:
+		return Goat
+	}
+	return Empty
+}
+
+// HasPlayer 檢查玩家是否參與此遊戲
+func (g *Game) HasPlayer(playerID string) bool {
+	return playerID != "" && (g.PlayerID == playerID || g.TigerPlayerID == playerID || g.GoatPlayerID == playerID)
+}
+
+// CanMove 檢查玩家是否可以移動指定陣營的棋子
+func (g *Game) CanMove(playerID string, side PieceType) bool {
+	if !g.HasPlayer(playerID) {
+		return false
+	}
+
+	var sidePlayerID string
+	switch side {
+	case Tiger:
+		sidePlayerID = g.TigerPlayerID
+	case Goat:
+		sidePlayerID = g.GoatPlayerID
+	}
+	if sidePlayerID != "" {
+		return sidePlayerID == playerID
+	}
+
+	// 未指定陣營的一方：AI對戰中由AI執棋，否則為同一設備輪流下棋，由創建者操作
+	if g.IsAIGame {
+		return false
+	}
+	return g.PlayerID == playerID
+}
+
+// aiPlayerPrefix AI虛擬玩家ID的前綴
+const aiPlayerPrefix = "ai:"
+
+// AIPlayerID 返回指定AI難度的虛擬玩家ID，用於積分等需要對手身份的場合
+func AIPlayerID(level int) string {
+	return fmt.Sprintf("%s%d", aiPlayerPrefix, level)
+}
+
+// ParseAIPlayerID 解析AI虛擬玩家ID，返回AI難度
+func ParseAIPlayerID(playerID string) (int, bool) {
+	levelText, found := strings.CutPrefix(playerID, aiPlayerPrefix)
+	if !found {
+		return 0, false
+	}
+	level, err := strconv.Atoi(levelText)
+	if err != nil {
+		return 0, false
+	}
+	return level, true
+}
+
+// SideAILevel 返回由AI執棋的一方的AI難度，該方不由AI執棋時返回 false
+func (g *Game) SideAILevel(side PieceType) (int, bool) {
+	var playerID string
+	switch side {
+	case Tiger:
+		playerID = g.TigerPlayerID
+	case Goat:
+		playerID = g.GoatPlayerID
+	default:
+		return 0, false
+	}
+	if level, ok := ParseAIPlayerID(playerID); ok {
+		return level, true
+	}
+	if playerID == "" && g.IsAIGame {
+		return g.AILevel, true
+	}
+	return 0, false
+}
+
+// SidePlayerID 返回執指定陣營的玩家ID，AI對戰中AI方返回其虛擬玩家ID
+func (g *Game) SidePlayerID(side PieceType) string {
+	var playerID string
+	switch side {
+	case Tiger:
+		playerID = g.TigerPlayerID
+	case Goat:
+		playerID = g.GoatPlayerID
+	}
+	if playerID == "" && g.IsAIGame {
+		return AIPlayerID(g.AILevel)
+	}
+	return playerID
+}
+
 // IsValidMove 檢查移動是否合法
 func (g *Game) IsValidMove(move Move) bool {
-	// TODO: 實現移動驗證邏輯
-	return true
+	_, ok := FindLegalMove(&g.State, move)
+	return ok
 }
 
-// MakeMove 執行一步移動
+// MakeMove 執行一步移動並記錄到移動歷史
 func (g *Game) MakeMove(move Move) error {
-	// TODO: 實現移動邏輯
+	legal, ok := FindLegalMove(&g.State, move)
+	if !ok {
+		return ErrInvalidMove
+	}
+
+	ApplyMove(&g.State, legal)
+	g.History = append(g.History, legal)
+	g.UpdatedAt = time.Now()
 	return nil
 }
 
+// MarshalJSON 在遊戲結束前省略種子，避免玩家預先算出AI的隨機選擇
+func (g Game) MarshalJSON() ([]byte, error) {
+	type plain Game
+	view := struct {
+		plain
+		Seed *int64 `json:"seed,omitempty"`
+	}{plain: plain(g)}
+	if g.State.IsGameOver {
+		view.Seed = &g.Seed
+	}
+	return json.Marshal(view)
+}
+
+// generateSeed 生成隨機種子
+func generateSeed() int64 {
+	var b [8]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return time.Now().UnixNano()
+	}
+	return int64(binary.BigEndian.Uint64(b[:]) >> 1)
+}
+
 // generateGameID 生成遊戲ID
+// 配對服務可能在同一秒內創建多局遊戲，因此在時間戳後附加隨機後綴
 func generateGameID() string {
-	// TODO: 實現遊戲ID生成邏輯
-	return "game_" + time.Now().Format("20060102150405")
+	suffix := make([]byte, 4)
+	if _, err := rand.Read(suffix); err != nil {
+		return "game_" + time.Now().Format("20060102150405.000000000")
+	}
+	return "game_" + time.Now().Format("20060102150405") + "_" + hex.EncodeToString(suffix)
 } 
\ No newline at end of file
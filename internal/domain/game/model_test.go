@@ -0,0 +1,29 @@
+package game
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestSeedHiddenUntilGameOver(t *testing.T) {
+	g := NewGame("tester", true, 1)
+	g.Seed = 12345
+
+	data, err := json.Marshal(g)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), `"seed"`) {
+		t.Errorf("seed exposed while the game is running: %s", data)
+	}
+
+	g.State.IsGameOver = true
+	data, err = json.Marshal(g)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"seed":12345`) {
+		t.Errorf("seed missing after the game ended: %s", data)
+	}
+}
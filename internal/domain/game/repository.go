@@ -11,6 +11,9 @@ type GameRepository interface {
 	// List 列出玩家的所有遊戲
 	List(playerID string) ([]*Game, error)
 
+	// ListFinished 列出所有已結束的遊戲
+	ListFinished() ([]*Game, error)
+
 	// Delete 刪除遊戲
 	Delete(id string) error
 } 
\ No newline at end of file
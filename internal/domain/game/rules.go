@@ -0,0 +1,164 @@
+package game
+
+import "errors"
+
+var ErrInvalidPly = errors.New("ply out of range")
+
+// CapturesToWin 虎吃掉多少隻羊即獲勝
+const CapturesToWin = 5
+
+// direction 表示棋盤上的一個移動方向
+type direction struct{ dx, dy int }
+
+var (
+	orthogonalDirections = []direction{{0, -1}, {-1, 0}, {1, 0}, {0, 1}}
+	diagonalDirections   = []direction{{-1, -1}, {1, -1}, {-1, 1}, {1, 1}}
+)
+
+// NewGameState 返回開局狀態：四角各一隻虎，20隻羊在手，羊先手
+func NewGameState() GameState {
+	state := GameState{
+		GoatsInHand: MaxGoats,
+		CurrentTurn: Goat,
+	}
+	state.Board[0][0] = Tiger
+	state.Board[0][BoardSize-1] = Tiger
+	state.Board[BoardSize-1][0] = Tiger
+	state.Board[BoardSize-1][BoardSize-1] = Tiger
+	return state
+}
+
+// inBounds 檢查位置是否在棋盤範圍內
+func inBounds(x, y int) bool {
+	return x >= 0 && x < BoardSize && y >= 0 && y < BoardSize
+}
+
+// directionsFrom 返回某點可走的方向：所有點都有直線，只有 (x+y) 為偶數的點有斜線
+func directionsFrom(x, y int) [][]direction {
+	if (x+y)%2 == 0 {
+		return [][]direction{orthogonalDirections, diagonalDirections}
+	}
+	return [][]direction{orthogonalDirections}
+}
+
+// LegalMoves 返回輪到的一方的所有合法移動，遊戲結束時返回空
+func LegalMoves(state *GameState) []Move {
+	if state.IsGameOver {
+		return nil
+	}
+
+	var moves []Move
+	board := &state.Board
+
+	// 放置階段：羊只能放到空位
+	if state.CurrentTurn == Goat && state.GoatsInHand > 0 {
+		for y := 0; y < BoardSize; y++ {
+			for x := 0; x < BoardSize; x++ {
+				if board[y][x] == Empty {
+					pos := Position{X: x, Y: y}
+					moves = append(moves, Move{From: pos, To: pos, PieceType: Goat})
+				}
+			}
+		}
+		return moves
+	}
+
+	for y := 0; y < BoardSize; y++ {
+		for x := 0; x < BoardSize; x++ {
+			if board[y][x] != state.CurrentTurn {
+				continue
+			}
+			from := Position{X: x, Y: y}
+			for _, group := range directionsFrom(x, y) {
+				for _, d := range group {
+					nx, ny := x+d.dx, y+d.dy
+					if !inBounds(nx, ny) {
+						continue
+					}
+					if board[ny][nx] == Empty {
+						moves = append(moves, Move{From: from, To: Position{X: nx, Y: ny}, PieceType: state.CurrentTurn})
+						continue
+					}
+
+					// 虎可沿直線跳過相鄰的羊吃子
+					jx, jy := x+2*d.dx, y+2*d.dy
+					if state.CurrentTurn == Tiger && board[ny][nx] == Goat && inBounds(jx, jy) && board[jy][jx] == Empty {
+						moves = append(moves, Move{
+							From:      from,
+							To:        Position{X: jx, Y: jy},
+							Capture:   &Position{X: nx, Y: ny},
+							PieceType: Tiger,
+						})
+					}
+				}
+			}
+		}
+	}
+	return moves
+}
+
+// FindLegalMove 在合法移動中查找起點、終點與棋子相同的一步，返回補全吃子資訊的移動
+func FindLegalMove(state *GameState, move Move) (Move, bool) {
+	for _, legal := range LegalMoves(state) {
+		if legal.From == move.From && legal.To == move.To && legal.PieceType == move.PieceType {
+			return legal, true
+		}
+	}
+	return Move{}, false
+}
+
+// ApplyMove 在狀態上執行一步已驗證的合法移動並切換回合，然後判定勝負
+func ApplyMove(state *GameState, move Move) {
+	if move.PieceType == Goat && state.GoatsInHand > 0 {
+		state.Board[move.To.Y][move.To.X] = Goat
+		state.GoatsInHand--
+	} else {
+		state.Board[move.From.Y][move.From.X] = Empty
+		state.Board[move.To.Y][move.To.X] = move.PieceType
+		if move.Capture != nil {
+			state.Board[move.Capture.Y][move.Capture.X] = Empty
+			state.CapturedGoats++
+		}
+	}
+
+	last := move
+	state.LastMove = &last
+	state.CurrentTurn = Opponent(state.CurrentTurn)
+	state.IsGameOver, state.Winner = Outcome(state)
+}
+
+// Outcome 判定勝負：虎吃滿5隻羊獲勝；輪到的一方無子可動則判負
+func Outcome(state *GameState) (bool, PieceType) {
+	if state.CapturedGoats >= CapturesToWin {
+		return true, Tiger
+	}
+	if len(LegalMoves(&GameState{
+		Board:       state.Board,
+		GoatsInHand: state.GoatsInHand,
+		CurrentTurn: state.CurrentTurn,
+	})) == 0 {
+		return true, Opponent(state.CurrentTurn)
+	}
+	return false, Empty
+}
+
+// Opponent 返回對方陣營
+func Opponent(side PieceType) PieceType {
+	if side == Tiger {
+		return Goat
+	}
+	return Tiger
+}
+
+// StateAtPly 從開局重放移動記錄，返回第 ply 步之後的局面
+func (g *Game) StateAtPly(ply int) (GameState, error) {
+	if ply < 0 || ply > len(g.History) {
+		return GameState{}, ErrInvalidPly
+	}
+
+	state := NewGameState()
+	for _, move := range g.History[:ply] {
+		ApplyMove(&state, move)
+	}
+	return state, nil
+}
@@ -0,0 +1,261 @@
+package game
+
+import (
+	"math/rand"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+// stateFrom 由棋盤圖建立局面，第 y 行第 x 個字元為 (x, y)：T 虎、G 羊、. 空
+func stateFrom(turn PieceType, goatsInHand, captured int, rows ...string) GameState {
+	state := GameState{CurrentTurn: turn, GoatsInHand: goatsInHand, CapturedGoats: captured}
+	for y, row := range rows {
+		for x, c := range row {
+			switch c {
+			case 'T':
+				state.Board[y][x] = Tiger
+			case 'G':
+				state.Board[y][x] = Goat
+			}
+		}
+	}
+	return state
+}
+
+// destinations 返回從 from 出發的所有合法移動的終點
+func destinations(state *GameState, from Position) []Position {
+	var to []Position
+	for _, move := range LegalMoves(state) {
+		if move.From == from {
+			to = append(to, move.To)
+		}
+	}
+	sort.Slice(to, func(i, j int) bool {
+		if to[i].Y != to[j].Y {
+			return to[i].Y < to[j].Y
+		}
+		return to[i].X < to[j].X
+	})
+	return to
+}
+
+func TestDiagonalParity(t *testing.T) {
+	tests := []struct {
+		name string
+		at   Position
+		want int
+	}{
+		{"centre has diagonals", Position{X: 2, Y: 2}, 8},
+		{"even point has diagonals", Position{X: 1, Y: 1}, 8},
+		{"odd point has no diagonals", Position{X: 1, Y: 2}, 4},
+		{"even edge point", Position{X: 2, Y: 0}, 5},
+		{"corner", Position{X: 0, Y: 0}, 3},
+		{"odd point next to corner", Position{X: 1, Y: 0}, 3},
+	}
+	for _, tt := range tests {
+		state := GameState{CurrentTurn: Tiger}
+		state.Board[tt.at.Y][tt.at.X] = Tiger
+		if got := len(destinations(&state, tt.at)); got != tt.want {
+			t.Errorf("%s %v: %d moves, want %d", tt.name, tt.at, got, tt.want)
+		}
+	}
+}
+
+func TestCaptures(t *testing.T) {
+	tests := []struct {
+		name    string
+		state   GameState
+		from    Position
+		to      Position
+		capture Position
+	}{
+		{
+			name:    "orthogonal capture during placement",
+			state:   stateFrom(Tiger, 15, 0, "TG...", ".....", ".....", ".....", "....."),
+			from:    Position{X: 0, Y: 0},
+			to:      Position{X: 2, Y: 0},
+			capture: Position{X: 1, Y: 0},
+		},
+		{
+			name:    "diagonal capture from an even point",
+			state:   stateFrom(Tiger, 0, 2, "T....", ".G...", ".....", ".....", "....."),
+			from:    Position{X: 0, Y: 0},
+			to:      Position{X: 2, Y: 2},
+			capture: Position{X: 1, Y: 1},
+		},
+		{
+			name:    "vertical capture in the movement phase",
+			state:   stateFrom(Tiger, 0, 0, ".....", ".....", "..T..", "..G..", "....."),
+			from:    Position{X: 2, Y: 2},
+			to:      Position{X: 2, Y: 4},
+			capture: Position{X: 2, Y: 3},
+		},
+	}
+	for _, tt := range tests {
+		state := tt.state
+		move, ok := FindLegalMove(&state, Move{From: tt.from, To: tt.to, PieceType: Tiger})
+		if !ok {
+			t.Errorf("%s: capture not legal", tt.name)
+			continue
+		}
+		if move.Capture == nil || *move.Capture != tt.capture {
+			t.Errorf("%s: capture = %v, want %v", tt.name, move.Capture, tt.capture)
+			continue
+		}
+
+		captured, inHand := state.CapturedGoats, state.GoatsInHand
+		ApplyMove(&state, move)
+		if state.Board[tt.capture.Y][tt.capture.X] != Empty || state.Board[tt.to.Y][tt.to.X] != Tiger || state.Board[tt.from.Y][tt.from.X] != Empty {
+			t.Errorf("%s: board after capture %v", tt.name, state.Board)
+		}
+		if state.CapturedGoats != captured+1 || state.GoatsInHand != inHand || state.CurrentTurn != Goat {
+			t.Errorf("%s: captured=%d inHand=%d turn=%d", tt.name, state.CapturedGoats, state.GoatsInHand, state.CurrentTurn)
+		}
+	}
+}
+
+func TestIllegalJumps(t *testing.T) {
+	tests := []struct {
+		name  string
+		state GameState
+		from  Position
+		to    Position
+	}{
+		{"over a tiger", stateFrom(Tiger, 0, 0, "TT...", ".....", ".....", ".....", "....."), Position{X: 0, Y: 0}, Position{X: 2, Y: 0}},
+		{"onto an occupied point", stateFrom(Tiger, 0, 0, "TGG..", ".....", ".....", ".....", "....."), Position{X: 0, Y: 0}, Position{X: 2, Y: 0}},
+		{"over two goats", stateFrom(Tiger, 0, 0, "TGG..", ".....", ".....", ".....", "....."), Position{X: 0, Y: 0}, Position{X: 3, Y: 0}},
+		{"diagonal from an odd point", stateFrom(Tiger, 0, 0, ".T...", "..G..", ".....", ".....", "....."), Position{X: 1, Y: 0}, Position{X: 3, Y: 2}},
+		{"over an empty point", stateFrom(Tiger, 0, 0, "T....", ".....", ".....", ".....", "....."), Position{X: 0, Y: 0}, Position{X: 2, Y: 0}},
+		{"knight-shaped jump", stateFrom(Tiger, 0, 0, "TG...", ".....", ".....", ".....", "....."), Position{X: 0, Y: 0}, Position{X: 2, Y: 1}},
+		{"goat jumping", stateFrom(Goat, 0, 0, "GT...", ".....", ".....", ".....", "....T"), Position{X: 0, Y: 0}, Position{X: 2, Y: 0}},
+		{"goat moving during placement", stateFrom(Goat, 5, 0, "G....", ".....", ".....", ".....", "....T"), Position{X: 0, Y: 0}, Position{X: 1, Y: 0}},
+	}
+	for _, tt := range tests {
+		state := tt.state
+		if _, ok := FindLegalMove(&state, Move{From: tt.from, To: tt.to, PieceType: state.CurrentTurn}); ok {
+			t.Errorf("%s: move %v -> %v accepted", tt.name, tt.from, tt.to)
+		}
+	}
+}
+
+func TestFifthCaptureWins(t *testing.T) {
+	state := stateFrom(Tiger, 0, CapturesToWin-1, "TG...", ".....", "..G..", ".....", "G...G")
+	move, ok := FindLegalMove(&state, Move{From: Position{X: 0, Y: 0}, To: Position{X: 2, Y: 0}, PieceType: Tiger})
+	if !ok {
+		t.Fatal("capture not legal")
+	}
+	ApplyMove(&state, move)
+	if !state.IsGameOver || state.Winner != Tiger {
+		t.Errorf("after 5th capture: over=%v winner=%d", state.IsGameOver, state.Winner)
+	}
+	if moves := LegalMoves(&state); len(moves) != 0 {
+		t.Errorf("finished game has %d legal moves", len(moves))
+	}
+}
+
+func TestNoMovesLoses(t *testing.T) {
+	tests := []struct {
+		name   string
+		state  GameState
+		winner PieceType
+	}{
+		{
+			name:   "trapped tiger in the movement phase",
+			state:  stateFrom(Tiger, 0, 0, "TGG..", "GG...", "G.G..", ".....", "....."),
+			winner: Goat,
+		},
+		{
+			name:   "trapped tiger during placement",
+			state:  stateFrom(Tiger, 14, 0, "TGG..", "GG...", "G.G..", ".....", "....."),
+			winner: Goat,
+		},
+		{
+			name:   "blocked goats",
+			state:  stateFrom(Goat, 0, 0, "GT...", "TT...", ".....", ".....", "....."),
+			winner: Tiger,
+		},
+	}
+	for _, tt := range tests {
+		over, winner := Outcome(&tt.state)
+		if !over || winner != tt.winner {
+			t.Errorf("%s: over=%v winner=%d, want winner %d", tt.name, over, winner, tt.winner)
+		}
+	}
+
+	// 羊的移動困住虎時立即獲勝
+	state := stateFrom(Goat, 0, 0, "TGG..", "G.G..", "G.G..", ".....", ".....")
+	move, ok := FindLegalMove(&state, Move{From: Position{X: 2, Y: 1}, To: Position{X: 1, Y: 1}, PieceType: Goat})
+	if !ok {
+		t.Fatal("goat move not legal")
+	}
+	ApplyMove(&state, move)
+	if !state.IsGameOver || state.Winner != Goat {
+		t.Errorf("after trapping: over=%v winner=%d", state.IsGameOver, state.Winner)
+	}
+}
+
+func TestStateAtPlyReplaysHistory(t *testing.T) {
+	rng := rand.New(rand.NewSource(3))
+	g := NewGame("tester", false, 0)
+	snapshots := []GameState{g.State}
+	for len(g.History) < 60 && !g.State.IsGameOver {
+		moves := LegalMoves(&g.State)
+		move := moves[rng.Intn(len(moves))]
+		// 只提交起點、終點及棋子，吃子資訊由 MakeMove 補全
+		if err := g.MakeMove(Move{From: move.From, To: move.To, PieceType: move.PieceType}); err != nil {
+			t.Fatal(err)
+		}
+		snapshots = append(snapshots, g.State)
+	}
+
+	for ply, want := range snapshots {
+		got, err := g.StateAtPly(ply)
+		if err != nil {
+			t.Fatalf("ply %d: %v", ply, err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Fatalf("ply %d: replayed state differs", ply)
+		}
+	}
+	for _, ply := range []int{-1, len(g.History) + 1} {
+		if _, err := g.StateAtPly(ply); err != ErrInvalidPly {
+			t.Errorf("ply %d: err = %v, want ErrInvalidPly", ply, err)
+		}
+	}
+}
+
+func TestCanonicalKeySymmetry(t *testing.T) {
+	rng := rand.New(rand.NewSource(5))
+	for i := 0; i < 30; i++ {
+		state := NewGameState()
+		for ply := rng.Intn(50); ply > 0 && !state.IsGameOver; ply-- {
+			moves := LegalMoves(&state)
+			ApplyMove(&state, moves[rng.Intn(len(moves))])
+		}
+
+		key, sym := CanonicalKey(&state)
+		canonical := sym.ApplyState(&state)
+		if PositionKey(&canonical) != key {
+			t.Fatalf("returned symmetry does not produce the canonical key")
+		}
+		for _, s := range Symmetries {
+			transformed := s.ApplyState(&state)
+			if got, _ := CanonicalKey(&transformed); got != key {
+				t.Fatalf("%v: canonical key differs", s)
+			}
+			// 對稱變換保持斜線結構，合法移動一一對應
+			if got, want := len(LegalMoves(&transformed)), len(LegalMoves(&state)); got != want {
+				t.Fatalf("%v: %d legal moves, want %d", s, got, want)
+			}
+			for _, move := range LegalMoves(&state) {
+				if _, ok := FindLegalMove(&transformed, s.ApplyMove(move)); !ok {
+					t.Fatalf("%v: transformed move %+v not legal", s, s.ApplyMove(move))
+				}
+			}
+			if back := s.Inverse().ApplyState(&transformed); PositionKey(&back) != PositionKey(&state) {
+				t.Fatalf("%v: inverse does not restore the position", s)
+			}
+		}
+	}
+}
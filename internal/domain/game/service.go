@@ -2,8 +2,7 @@ package game
 
 import (
 	"errors"
-	"log"
-	"math"
+	"time"
 )
 
 var (
@@ -11,27 +10,68 @@ var (
 	ErrGameNotFound   = errors.New("game not found")
 	ErrNotPlayersTurn = errors.New("not player's turn")
 	ErrGameOver       = errors.New("game is already over")
+	ErrPlayerNotFound = errors.New("player not found")
+	ErrForbidden      = errors.New("player is not allowed to access this game")
+	ErrGameNotOver    = errors.New("game is not over yet")
+	ErrNoAIEngine     = errors.New("no AI engine for level")
+	ErrRatedSeed      = errors.New("seed cannot be chosen for rated games")
 )
 
 type GameService struct {
 	repository GameRepository
-	aiEngine   AIEngine
+	engines    AIEngineSelector
+	players    PlayerDirectory
+
+	gameOverHandlers []GameOverHandler
 }
 
+// GameOverHandler 在遊戲結束時被調用
+type GameOverHandler func(game *Game)
+
+// maxConsecutiveAIMoves 雙方均為AI時連續自動下棋的步數上限，超過則判和
+const maxConsecutiveAIMoves = 400
+
 type AIEngine interface {
 	CalculateNextMove(game *Game) (*Move, error)
 }
 
-func NewGameService(repository GameRepository, aiEngine AIEngine) *GameService {
+// AIEngineSelector 按AI難度選擇引擎，沒有可用引擎時返回 nil
+type AIEngineSelector interface {
+	EngineForLevel(level int) AIEngine
+}
+
+// PlayerDirectory 用於確認遊戲引用的玩家確實已註冊
+type PlayerDirectory interface {
+	Exists(playerID string) (bool, error)
+}
+
+func NewGameService(repository GameRepository, engines AIEngineSelector, players PlayerDirectory) *GameService {
 	return &GameService{
 		repository: repository,
-		aiEngine:   aiEngine,
+		engines:    engines,
+		players:    players,
 	}
 }
 
-// CreateGame 創建新遊戲
-func (s *GameService) CreateGame(playerID string, isAIGame bool, aiLevel int) (*Game, error) {
+// OnGameOver 註冊遊戲結束事件處理器，需在服務開始處理請求前註冊
+func (s *GameService) OnGameOver(handler GameOverHandler) {
+	s.gameOverHandlers = append(s.gameOverHandlers, handler)
+}
+
+// CreateGame 創建新遊戲，seed 為空時隨機生成種子
+// 計分遊戲不能指定種子，否則玩家可預先算出AI的回應
+func (s *GameService) CreateGame(playerID string, isAIGame bool, aiLevel int, rated bool, seed *int64) (*Game, error) {
+	if rated && seed != nil {
+		return nil, ErrRatedSeed
+	}
+	if err := s.requirePlayers(playerID); err != nil {
+		return nil, err
+	}
 	game := NewGame(playerID, isAIGame, aiLevel)
+	game.Rated = rated
+	if seed != nil {
+		game.Seed = *seed
+	}
 	err := s.repository.Save(game)
 	if err != nil {
 		return nil, err
@@ -39,226 +79,246 @@ func (s *GameService) CreateGame(playerID string, isAIGame bool, aiLevel int) (*
 	return game, nil
 }
 
-// MakeMove 執行移動並處理遊戲邏輯
-func (s *GameService) MakeMove(gameID string, move Move) (*Game, error) {
-	game, err := s.repository.GetByID(gameID)
+// CreateMatchedGame 為配對成功的兩位玩家創建遊戲
+// 若先手方為AI，需調用 AdvanceAI 讓AI開始下棋
+func (s *GameService) CreateMatchedGame(tigerPlayerID, goatPlayerID string, timeControl *TimeControl, rated bool) (*Game, error) {
+	if err := s.requirePlayers(tigerPlayerID, goatPlayerID); err != nil {
+		return nil, err
+	}
+	game := NewMatchedGame(tigerPlayerID, goatPlayerID, timeControl, rated)
+	err := s.repository.Save(game)
 	if err != nil {
-		return nil, ErrGameNotFound
+		return nil, err
 	}
+	return game, nil
+}
 
-	if game.State.IsGameOver {
-		return nil, ErrGameOver
+// CreateRematch 為已結束的遊戲創建交換陣營的再戰，若先手方為AI則立即下出第一步
+func (s *GameService) CreateRematch(gameID, seriesID string) (*Game, error) {
+	original, err := s.repository.GetByID(gameID)
+	if err != nil {
+		return nil, ErrGameNotFound
+	}
+	if !original.State.IsGameOver {
+		return nil, ErrGameNotOver
 	}
 
-	if !s.IsValidMove(game, move) {
-		return nil, ErrInvalidMove
+	game := NewRematchGame(original)
+	game.SeriesID = seriesID
+	if err := s.playAITurns(game); err != nil {
+		return nil, err
+	}
+	if err := s.repository.Save(game); err != nil {
+		return nil, err
 	}
+	return game, nil
+}
 
-	// 執行移動
-	err = s.executeMove(game, move)
+// AdvanceAI 在輪到AI時讓AI下棋，直到輪到人類玩家或遊戲結束
+func (s *GameService) AdvanceAI(gameID string) (*Game, error) {
+	game, err := s.repository.GetByID(gameID)
 	if err != nil {
+		return nil, ErrGameNotFound
+	}
+
+	if err := s.playAITurns(game); err != nil {
 		return nil, err
 	}
 
-	// 檢查遊戲是否結束
-	s.checkGameOver(game)
+	if err := s.repository.Save(game); err != nil {
+		return nil, err
+	}
+	return game, nil
+}
 
-	// 如果是AI遊戲且遊戲未結束，執行AI移動
-	if game.IsAIGame && !game.State.IsGameOver {
-		aiMove, err := s.aiEngine.CalculateNextMove(game)
-		if err != nil {
-			return nil, err
+// playAITurns 連續執行AI移動，直到輪到人類玩家或遊戲結束
+func (s *GameService) playAITurns(game *Game) error {
+	for moves := 0; !game.State.IsGameOver; moves++ {
+		level, isAI := game.SideAILevel(game.State.CurrentTurn)
+		if !isAI {
+			return nil
+		}
+
+		// 雙方均為AI時可能無限循環，超過上限判和
+		if moves >= maxConsecutiveAIMoves {
+			s.finishGame(game, Empty)
+			return nil
 		}
-		err = s.executeMove(game, *aiMove)
+
+		// 按輪到的一方的AI難度選擇引擎
+		engine := s.engines.EngineForLevel(level)
+		if engine == nil {
+			return ErrNoAIEngine
+		}
+		aiMove, err := engine.CalculateNextMove(game)
 		if err != nil {
-			return nil, err
+			return err
+		}
+		if aiMove == nil {
+			return nil
+		}
+		if err := s.executeMove(game, *aiMove); err != nil {
+			return err
 		}
 		s.checkGameOver(game)
 	}
-
-	// 保存遊戲狀態
-	err = s.repository.Save(game)
-	if err != nil {
-		return nil, err
-	}
-
-	return game, nil
+	return nil
 }
 
-// IsValidMove 檢查移動是否合法
-func (s *GameService) IsValidMove(game *Game, move Move) bool {
-	// 檢查是否是玩家的回合
-	if game.State.CurrentTurn != move.PieceType {
-		return false
+// requirePlayers 確認所有玩家都已註冊，AI虛擬玩家不需要註冊
+func (s *GameService) requirePlayers(playerIDs ...string) error {
+	for _, playerID := range playerIDs {
+		if _, isAI := ParseAIPlayerID(playerID); isAI {
+			continue
+		}
+		exists, err := s.players.Exists(playerID)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return ErrPlayerNotFound
+		}
 	}
+	return nil
+}
 
-	// 檢查目標位置是否為空
-	if game.State.Board[move.To.Y][move.To.X] != Empty {
-		return false
+// MakeMove 代表玩家執行移動並處理遊戲邏輯
+func (s *GameService) MakeMove(gameID, playerID string, move Move) (*Game, error) {
+	game, err := s.repository.GetByID(gameID)
+	if err != nil {
+		return nil, ErrGameNotFound
 	}
 
-	// 如果是放置羊的階段
-	if move.PieceType == Goat && game.State.GoatsInHand > 0 {
-		return move.From.X == move.To.X && move.From.Y == move.To.Y
+	if !game.HasPlayer(playerID) {
+		return nil, ErrForbidden
 	}
 
-	// 對於非放置階段的移動，檢查起點是否有正確的棋子
-	if game.State.Board[move.From.Y][move.From.X] != move.PieceType {
-		return false
+	if game.State.IsGameOver {
+		return nil, ErrGameOver
 	}
 
-	// 檢查起點是否有正確的棋子
-	if game.State.Board[move.From.Y][move.From.X] != move.PieceType {
-		return false
+	if !game.CanMove(playerID, move.PieceType) {
+		return nil, ErrNotPlayersTurn
 	}
 
-	// 檢查目標位置是否為空
-	if game.State.Board[move.To.Y][move.To.X] != Empty {
-		return false
+	if !s.IsValidMove(game, move) {
+		return nil, ErrInvalidMove
 	}
 
-	// 如果是放置羊的階段
-	if move.PieceType == Goat && game.State.GoatsInHand > 0 {
-		return move.From.X == move.To.X && move.From.Y == move.To.Y
+	// 執行移動
+	err = s.executeMove(game, move)
+	if err != nil {
+		return nil, err
 	}
 
-	// 檢查移動距離
-	dx := math.Abs(float64(move.To.X - move.From.X))
-	dy := math.Abs(float64(move.To.Y - move.From.Y))
+	// 檢查遊戲是否結束
+	s.checkGameOver(game)
 
-	// 正常移動：只能移動到相鄰的點
-	if dx <= 1 && dy <= 1 {
-		return true
+	// 如果輪到AI且遊戲未結束，執行AI移動
+	err = s.playAITurns(game)
+	if err != nil {
+		return nil, err
 	}
 
-	// 虎吃羊：檢查是否是有效的跳躍
-	if move.PieceType == Tiger && dx <= 2 && dy <= 2 {
-		// 計算中間位置
-		midX := (move.From.X + move.To.X) / 2
-		midY := (move.From.Y + move.To.Y) / 2
-
-		// 檢查中間是否有羊
-		if game.State.Board[midY][midX] == Goat {
-			move.Capture = &Position{X: midX, Y: midY}
-			return true
-		}
+	// 保存遊戲狀態
+	err = s.repository.Save(game)
+	if err != nil {
+		return nil, err
 	}
 
-	return false
+	return game, nil
+}
+
+// IsValidMove 檢查移動是否合法
+func (s *GameService) IsValidMove(game *Game, move Move) bool {
+	return game.IsValidMove(move)
 }
 
 // executeMove 執行移動
 func (s *GameService) executeMove(game *Game, move Move) error {
-	// 如果是放置羊的階段
-	if move.PieceType == Goat && game.State.GoatsInHand > 0 {
-		game.State.Board[move.To.Y][move.To.X] = Goat
-		game.State.GoatsInHand--
-	} else {
-		// 移動棋子
-		game.State.Board[move.From.Y][move.From.X] = Empty
-		game.State.Board[move.To.Y][move.To.X] = move.PieceType
-
-		// 處理吃子
-		isValid := (move.To.X+move.From.X)%2 == 0 && (move.To.Y+move.From.Y)%2 == 0
-		if isValid == true && game.State.Board[(move.To.Y+move.From.Y)/2][(move.To.X+move.From.X)/2] == Goat {
-			game.State.Board[(move.To.Y+move.From.Y)/2][(move.To.X+move.From.X)/2] = Empty
-			game.State.CapturedGoats++
-		}
+	return game.MakeMove(move)
+}
+
+// checkGameOver 檢查遊戲是否結束：虎吃滿5隻羊，或輪到的一方無子可動
+func (s *GameService) checkGameOver(game *Game) {
+	if over, winner := Outcome(&game.State); over {
+		s.finishGame(game, winner)
 	}
+}
 
-	// 更新最後一步
-	game.State.LastMove = &move
+// finishGame 結束遊戲並通知事件處理器，winner 為 Empty 表示和棋
+func (s *GameService) finishGame(game *Game, winner PieceType) {
+	now := time.Now()
+	game.State.IsGameOver = true
+	game.State.Winner = winner
+	game.FinishedAt = &now
+	game.UpdatedAt = now
+	s.notifyGameOver(game)
+}
 
-	// 切換回合
-	if game.State.CurrentTurn == Tiger {
-		game.State.CurrentTurn = Goat
-	} else {
-		game.State.CurrentTurn = Tiger
+// notifyGameOver 通知所有遊戲結束事件處理器
+func (s *GameService) notifyGameOver(game *Game) {
+	for _, handler := range s.gameOverHandlers {
+		handler(game)
 	}
-	log.Printf("executeMove2：%d", game.State.CurrentTurn)
+}
 
-	return nil
+// GetGame 根據ID獲取遊戲
+func (s *GameService) GetGame(id string) (*Game, error) {
+	return s.repository.GetByID(id)
 }
 
-// checkGameOver 檢查遊戲是否結束
-func (s *GameService) checkGameOver(game *Game) {
-	// 檢查虎是否獲勝（吃掉5隻羊）
-	if game.State.CapturedGoats >= 5 {
-		log.Printf("檢查虎是否獲勝（吃掉5隻羊）")
-		game.State.IsGameOver = true
-		game.State.Winner = Tiger
-		return
+// RecordHint 記錄玩家使用了一次提示，該遊戲不再計入積分
+func (s *GameService) RecordHint(gameID string) (*Game, error) {
+	game, err := s.repository.GetByID(gameID)
+	if err != nil {
+		return nil, ErrGameNotFound
 	}
 
-	// 檢查羊是否獲勝（虎無法移動）
-	if s.isTigerTrapped(game) || game.State.GoatsInHand == 0 {
-		log.Printf("檢查羊是否獲勝（虎無法移動）")
-		game.State.IsGameOver = true
-		game.State.Winner = Goat
-		return
+	game.HintsUsed++
+	game.Rated = false
+	game.UpdatedAt = time.Now()
+	if err := s.repository.Save(game); err != nil {
+		return nil, err
 	}
+	return game, nil
 }
 
-// isTigerTrapped 檢查虎是否無法移動
-func (s *GameService) isTigerTrapped(game *Game) bool {
-	// 對每隻虎檢查是否有可行的移動
-	for y := 0; y < BoardSize; y++ {
-		for x := 0; x < BoardSize; x++ {
-			if game.State.Board[y][x] == Tiger {
-				if s.hasTigerMoves(game, x, y) {
-					return false
-				}
-			}
-		}
+// SetAnnotation 保存已結束遊戲的賽後註解
+func (s *GameService) SetAnnotation(gameID string, annotation *Annotation) error {
+	game, err := s.repository.GetByID(gameID)
+	if err != nil {
+		return ErrGameNotFound
 	}
-	return true
-}
-
-// hasTigerMoves 檢查特定位置的虎是否有可行的移動
-func (s *GameService) hasTigerMoves(game *Game, x, y int) bool {
-	// 檢查所有可能的移動方向
-	log.Printf("檢查虎子在位置(%d,%d)的可能移動", x, y)
-	directions := []struct{ dx, dy int }{
-		{-1, -1}, {-1, 0}, {-1, 1},
-		{0, -1}, {0, 1},
-		{1, -1}, {1, 0}, {1, 1},
-	}
-
-	for _, d := range directions {
-		newX, newY := x+d.dx, y+d.dy
-		// 檢查普通移動
-		if newX >= 0 && newX < BoardSize && newY >= 0 && newY < BoardSize {
-			log.Printf("檢查普通移動到(%d,%d)", newX, newY)
-			if game.State.Board[newY][newX] == Empty {
-				log.Printf("找到有效的普通移動")
-				return true
-			}
-		}
-
-		// 檢查跳躍移動（吃子）
-		jumpX, jumpY := x+2*d.dx, y+2*d.dy
-		if jumpX >= 0 && jumpX < BoardSize && jumpY >= 0 && jumpY < BoardSize {
-			if game.State.Board[jumpY][jumpX] == Empty {
-				return true
-			}
-		}
+	if !game.State.IsGameOver {
+		return ErrGameNotOver
 	}
 
-	log.Printf("該虎子沒有可用的移動")
-	return false
+	game.Annotation = annotation
+	return s.repository.Save(game)
 }
 
-// GetGame 根據ID獲取遊戲
-func (s *GameService) GetGame(id string) (*Game, error) {
-	return s.repository.GetByID(id)
+// ListFinishedGames 獲取所有已結束的遊戲
+func (s *GameService) ListFinishedGames() ([]*Game, error) {
+	return s.repository.ListFinished()
 }
 
 // ListPlayerGames 獲取玩家的所有遊戲
 func (s *GameService) ListPlayerGames(playerID string) ([]*Game, error) {
+	if err := s.requirePlayers(playerID); err != nil {
+		return nil, err
+	}
 	return s.repository.List(playerID)
 }
 
-// DeleteGame 刪除遊戲
-func (s *GameService) DeleteGame(id string) error {
+// DeleteGame 代表玩家刪除遊戲，只有參與者可以刪除
+func (s *GameService) DeleteGame(id, playerID string) error {
+	game, err := s.repository.GetByID(id)
+	if err != nil {
+		return ErrGameNotFound
+	}
+	if !game.HasPlayer(playerID) {
+		return ErrForbidden
+	}
 	return s.repository.Delete(id)
 }
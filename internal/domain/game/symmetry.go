@@ -0,0 +1,111 @@
+package game
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Symmetry 表示棋盤的八種對稱變換之一（旋轉及翻轉）
+// 這些變換保持 (x+y) 的奇偶性，因此斜線結構不變，局面的勝負性質相同
+type Symmetry int
+
+const (
+	Identity      Symmetry = iota
+	Rotate90               // (x, y) -> (N-y, x)
+	Rotate180              // (x, y) -> (N-x, N-y)
+	Rotate270              // (x, y) -> (y, N-x)
+	FlipX                  // (x, y) -> (N-x, y)
+	FlipY                  // (x, y) -> (x, N-y)
+	Transpose              // (x, y) -> (y, x)
+	AntiTranspose          // (x, y) -> (N-y, N-x)
+)
+
+// Symmetries 所有對稱變換
+var Symmetries = [...]Symmetry{Identity, Rotate90, Rotate180, Rotate270, FlipX, FlipY, Transpose, AntiTranspose}
+
+// Apply 對位置進行變換
+func (s Symmetry) Apply(p Position) Position {
+	const n = BoardSize - 1
+	switch s {
+	case Rotate90:
+		return Position{X: n - p.Y, Y: p.X}
+	case Rotate180:
+		return Position{X: n - p.X, Y: n - p.Y}
+	case Rotate270:
+		return Position{X: p.Y, Y: n - p.X}
+	case FlipX:
+		return Position{X: n - p.X, Y: p.Y}
+	case FlipY:
+		return Position{X: p.X, Y: n - p.Y}
+	case Transpose:
+		return Position{X: p.Y, Y: p.X}
+	case AntiTranspose:
+		return Position{X: n - p.Y, Y: n - p.X}
+	}
+	return p
+}
+
+// Inverse 返回逆變換
+func (s Symmetry) Inverse() Symmetry {
+	switch s {
+	case Rotate90:
+		return Rotate270
+	case Rotate270:
+		return Rotate90
+	}
+	return s
+}
+
+// ApplyMove 對移動進行變換
+func (s Symmetry) ApplyMove(move Move) Move {
+	transformed := Move{
+		From:      s.Apply(move.From),
+		To:        s.Apply(move.To),
+		PieceType: move.PieceType,
+	}
+	if move.Capture != nil {
+		capture := s.Apply(*move.Capture)
+		transformed.Capture = &capture
+	}
+	return transformed
+}
+
+// ApplyState 對局面進行變換，回合、手上及被吃的羊數不變
+func (s Symmetry) ApplyState(state *GameState) GameState {
+	transformed := *state
+	for y := 0; y < BoardSize; y++ {
+		for x := 0; x < BoardSize; x++ {
+			p := s.Apply(Position{X: x, Y: y})
+			transformed.Board[p.Y][p.X] = state.Board[y][x]
+		}
+	}
+	if state.LastMove != nil {
+		last := s.ApplyMove(*state.LastMove)
+		transformed.LastMove = &last
+	}
+	return transformed
+}
+
+// PositionKey 返回局面的唯一標識，只包含影響後續走法的資訊
+func PositionKey(state *GameState) string {
+	var b strings.Builder
+	for _, row := range state.Board {
+		for _, piece := range row {
+			fmt.Fprintf(&b, "%d", piece)
+		}
+	}
+	fmt.Fprintf(&b, "/%d/%d/%d", state.GoatsInHand, state.CapturedGoats, state.CurrentTurn)
+	return b.String()
+}
+
+// CanonicalKey 返回八種對稱變換中字典序最小的局面標識，以及把局面變換為該標準形的對稱
+func CanonicalKey(state *GameState) (string, Symmetry) {
+	bestKey, bestSym := PositionKey(state), Identity
+	for _, sym := range Symmetries[1:] {
+		transformed := sym.ApplyState(state)
+		if key := PositionKey(&transformed); key < bestKey {
+			bestKey, bestSym = key, sym
+		}
+	}
+	return bestKey, bestSym
+}
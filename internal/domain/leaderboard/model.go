@@ -0,0 +1,99 @@
+package leaderboard
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/nelawu/BagchalGolang/internal/domain/game"
+)
+
+// Metric 表示排行榜的排序指標
+type Metric string
+
+const (
+	MetricRating       Metric = "rating"
+	MetricTigerWinRate Metric = "tiger_win_rate"
+	MetricGoatWinRate  Metric = "goat_win_rate"
+	MetricGamesPlayed  Metric = "games_played"
+)
+
+// Period 表示統計時段
+type Period string
+
+const (
+	PeriodDaily   Period = "daily"
+	PeriodMonthly Period = "monthly"
+	PeriodAllTime Period = "all_time"
+)
+
+// 時間控制篩選值
+const (
+	TimeControlAll     = "all"
+	TimeControlUntimed = "untimed"
+)
+
+// PlayerStats 表示玩家在某一時段與時間控制下的戰績
+type PlayerStats struct {
+	PlayerID    string `json:"playerId"`
+	GamesPlayed int    `json:"gamesPlayed"`
+	TigerGames  int    `json:"tigerGames"`
+	TigerWins   int    `json:"tigerWins"`
+	GoatGames   int    `json:"goatGames"`
+	GoatWins    int    `json:"goatWins"`
+	Draws       int    `json:"draws"`
+}
+
+// TigerWinRate 執虎勝率
+func (s *PlayerStats) TigerWinRate() float64 {
+	if s.TigerGames == 0 {
+		return 0
+	}
+	return float64(s.TigerWins) / float64(s.TigerGames)
+}
+
+// GoatWinRate 執羊勝率
+func (s *PlayerStats) GoatWinRate() float64 {
+	if s.GoatGames == 0 {
+		return 0
+	}
+	return float64(s.GoatWins) / float64(s.GoatGames)
+}
+
+// Entry 表示排行榜中的一行
+type Entry struct {
+	Rank  int         `json:"rank"`
+	Value float64     `json:"value"`
+	Stats PlayerStats `json:"stats"`
+}
+
+// Leaderboard 表示一份排行榜
+type Leaderboard struct {
+	Metric      Metric    `json:"metric"`
+	Period      Period    `json:"period"`
+	PeriodKey   string    `json:"periodKey"` // 如 2026-10-18、2026-10 或 all
+	TimeControl string    `json:"timeControl"`
+	Entries     []Entry   `json:"entries"`
+	GeneratedAt time.Time `json:"generatedAt"`
+}
+
+// periodKey 返回時間點所屬的時段鍵（UTC）
+func periodKey(period Period, t time.Time) string {
+	t = t.UTC()
+	switch period {
+	case PeriodDaily:
+		return t.Format("2006-01-02")
+	case PeriodMonthly:
+		return t.Format("2006-01")
+	default:
+		return "all"
+	}
+}
+
+// TimeControlKey 返回時間控制的篩選鍵，如 300_5，不限時為 untimed
+// 不使用 +，因為查詢字串中的 + 會被解碼為空格
+func TimeControlKey(timeControl *game.TimeControl) string {
+	if timeControl == nil || (timeControl.InitialSeconds == 0 && timeControl.IncrementSeconds == 0) {
+		return TimeControlUntimed
+	}
+	return fmt.Sprintf("%d_%d", timeControl.InitialSeconds, timeControl.IncrementSeconds)
+}
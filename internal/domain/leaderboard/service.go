@@ -0,0 +1,225 @@
+package leaderboard
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/nelawu/BagchalGolang/internal/domain/game"
+)
+
+var (
+	ErrInvalidMetric = errors.New("invalid leaderboard metric")
+	ErrInvalidPeriod = errors.New("invalid leaderboard period")
+)
+
+// 排行榜預設參數
+const (
+	DefaultLimit    = 50
+	MaxLimit        = 200
+	DefaultMinGames = 5 // 勝率榜的最少對局數
+)
+
+// GameSource 提供已結束的遊戲，用於啟動時重建排行榜
+type GameSource interface {
+	ListFinishedGames() ([]*game.Game, error)
+}
+
+// RatingSource 提供玩家當前積分
+type RatingSource interface {
+	Rating(playerID string, side game.PieceType) float64
+}
+
+// bucketKey 標識一組統計：時段 + 時段鍵 + 時間控制
+type bucketKey struct {
+	period      Period
+	periodKey   string
+	timeControl string
+}
+
+// Service 維護排行榜統計，啟動時從已結束遊戲重建，之後由遊戲結束事件增量更新
+type Service struct {
+	ratings RatingSource
+
+	mu        sync.RWMutex
+	buckets   map[bucketKey]map[string]*PlayerStats
+	processed map[string]bool // 已計入的遊戲ID
+}
+
+func NewService(ratings RatingSource) *Service {
+	return &Service{
+		ratings:   ratings,
+		buckets:   make(map[bucketKey]map[string]*PlayerStats),
+		processed: make(map[string]bool),
+	}
+}
+
+// Rebuild 從已結束的遊戲重建統計
+func (s *Service) Rebuild(source GameSource) error {
+	games, err := source.ListFinishedGames()
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.buckets = make(map[bucketKey]map[string]*PlayerStats)
+	s.processed = make(map[string]bool)
+	for _, g := range games {
+		s.recordLocked(g)
+	}
+	s.pruneLocked(time.Now())
+	return nil
+}
+
+// HandleGameOver 將剛結束的遊戲計入統計
+func (s *Service) HandleGameOver(g *game.Game) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.recordLocked(g)
+	s.pruneLocked(time.Now())
+}
+
+// recordLocked 將一局計分遊戲計入所有相關的統計組，調用前需持有鎖
+// 非計分遊戲（包括使用過提示的遊戲）不計入，與積分保持一致
+func (s *Service) recordLocked(g *game.Game) {
+	if !g.Rated || !g.State.IsGameOver || s.processed[g.ID] {
+		return
+	}
+	tigerID := g.SidePlayerID(game.Tiger)
+	goatID := g.SidePlayerID(game.Goat)
+	if tigerID == "" || goatID == "" || tigerID == goatID {
+		return
+	}
+	s.processed[g.ID] = true
+
+	finishedAt := g.UpdatedAt
+	if g.FinishedAt != nil {
+		finishedAt = *g.FinishedAt
+	}
+
+	for _, period := range []Period{PeriodDaily, PeriodMonthly, PeriodAllTime} {
+		for _, timeControl := range []string{TimeControlAll, TimeControlKey(g.TimeControl)} {
+			key := bucketKey{period: period, periodKey: periodKey(period, finishedAt), timeControl: timeControl}
+			bucket, exists := s.buckets[key]
+			if !exists {
+				bucket = make(map[string]*PlayerStats)
+				s.buckets[key] = bucket
+			}
+			addResult(bucket, tigerID, game.Tiger, g.State.Winner)
+			addResult(bucket, goatID, game.Goat, g.State.Winner)
+		}
+	}
+}
+
+// pruneLocked 清理已過去的日榜、月榜統計，調用前需持有鎖
+func (s *Service) pruneLocked(now time.Time) {
+	for key := range s.buckets {
+		if key.period != PeriodAllTime && key.periodKey < periodKey(key.period, now.AddDate(0, 0, -1)) {
+			delete(s.buckets, key)
+		}
+	}
+}
+
+// addResult 將一方的結果計入統計組
+func addResult(bucket map[string]*PlayerStats, playerID string, side, winner game.PieceType) {
+	stats, exists := bucket[playerID]
+	if !exists {
+		stats = &PlayerStats{PlayerID: playerID}
+		bucket[playerID] = stats
+	}
+
+	stats.GamesPlayed++
+	if winner == game.Empty {
+		stats.Draws++
+	}
+	switch side {
+	case game.Tiger:
+		stats.TigerGames++
+		if winner == game.Tiger {
+			stats.TigerWins++
+		}
+	case game.Goat:
+		stats.GoatGames++
+		if winner == game.Goat {
+			stats.GoatWins++
+		}
+	}
+}
+
+// GetLeaderboard 返回指定指標、時段與時間控制的排行榜，AI虛擬玩家不列入排名
+func (s *Service) GetLeaderboard(metric Metric, period Period, timeControl string, limit, minGames int) (*Leaderboard, error) {
+	switch metric {
+	case MetricRating, MetricTigerWinRate, MetricGoatWinRate, MetricGamesPlayed:
+	default:
+		return nil, ErrInvalidMetric
+	}
+	switch period {
+	case PeriodDaily, PeriodMonthly, PeriodAllTime:
+	default:
+		return nil, ErrInvalidPeriod
+	}
+	if timeControl == "" {
+		timeControl = TimeControlAll
+	}
+	if limit <= 0 || limit > MaxLimit {
+		limit = DefaultLimit
+	}
+
+	now := time.Now()
+	key := bucketKey{period: period, periodKey: periodKey(period, now), timeControl: timeControl}
+
+	s.mu.RLock()
+	entries := make([]Entry, 0, len(s.buckets[key]))
+	for playerID, stats := range s.buckets[key] {
+		if _, isAI := game.ParseAIPlayerID(playerID); isAI {
+			continue
+		}
+
+		var value float64
+		switch metric {
+		case MetricRating:
+			value = s.ratings.Rating(playerID, game.Empty)
+		case MetricTigerWinRate:
+			if stats.TigerGames < minGames {
+				continue
+			}
+			value = stats.TigerWinRate()
+		case MetricGoatWinRate:
+			if stats.GoatGames < minGames {
+				continue
+			}
+			value = stats.GoatWinRate()
+		case MetricGamesPlayed:
+			value = float64(stats.GamesPlayed)
+		}
+		entries = append(entries, Entry{Value: value, Stats: *stats})
+	}
+	s.mu.RUnlock()
+
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Value != entries[j].Value {
+			return entries[i].Value > entries[j].Value
+		}
+		if entries[i].Stats.GamesPlayed != entries[j].Stats.GamesPlayed {
+			return entries[i].Stats.GamesPlayed > entries[j].Stats.GamesPlayed
+		}
+		return entries[i].Stats.PlayerID < entries[j].Stats.PlayerID
+	})
+	if len(entries) > limit {
+		entries = entries[:limit]
+	}
+	for i := range entries {
+		entries[i].Rank = i + 1
+	}
+
+	return &Leaderboard{
+		Metric:      metric,
+		Period:      period,
+		PeriodKey:   key.periodKey,
+		TimeControl: timeControl,
+		Entries:     entries,
+		GeneratedAt: now,
+	}, nil
+}
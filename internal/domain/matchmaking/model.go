@@ -0,0 +1,60 @@
+package matchmaking
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+
+	"github.com/nelawu/BagchalGolang/internal/domain/game"
+)
+
+// TicketStatus 表示配對票的狀態
+type TicketStatus string
+
+const (
+	StatusWaiting   TicketStatus = "waiting"
+	StatusMatched   TicketStatus = "matched"
+	StatusCancelled TicketStatus = "cancelled"
+)
+
+// Ticket 表示一位玩家的配對請求
+type Ticket struct {
+	ID          string           `json:"id"`
+	PlayerID    string           `json:"playerId"`
+	Side        game.PieceType   `json:"side"` // 期望陣營，Empty 表示不限
+	TimeControl game.TimeControl `json:"timeControl"`
+	Rating      float64          `json:"rating"`
+	Status      TicketStatus     `json:"status"`
+	GameID      string           `json:"gameId,omitempty"` // 配對成功後的遊戲ID
+	AssignedTo  game.PieceType   `json:"assignedTo"`       // 配對成功後分配的陣營
+	OpponentID  string           `json:"opponentId,omitempty"`
+	EnqueuedAt  time.Time        `json:"enqueuedAt"`
+	ResolvedAt  *time.Time       `json:"resolvedAt,omitempty"` // 配對成功或取消的時間
+
+	// done 在票據狀態離開 waiting 時關閉，用於長輪詢通知
+	done chan struct{}
+}
+
+// Window 返回票據在當前時間可接受的積分差距
+func (t *Ticket) Window(cfg Config, now time.Time) float64 {
+	waited := now.Sub(t.EnqueuedAt).Seconds()
+	window := cfg.InitialWindow + cfg.WindowGrowthPerSecond*waited
+	if window > cfg.MaxWindow {
+		window = cfg.MaxWindow
+	}
+	return window
+}
+
+// accepts 檢查票據是否願意執指定陣營
+func (t *Ticket) accepts(side game.PieceType) bool {
+	return t.Side == game.Empty || t.Side == side
+}
+
+// generateTicketID 生成票據ID
+func generateTicketID() string {
+	suffix := make([]byte, 6)
+	if _, err := rand.Read(suffix); err != nil {
+		return "ticket_" + time.Now().Format("20060102150405.000000000")
+	}
+	return "ticket_" + hex.EncodeToString(suffix)
+}
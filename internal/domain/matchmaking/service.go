@@ -0,0 +1,312 @@
+package matchmaking
+
+import (
+	"context"
+	"errors"
+	"log"
+	"math"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/nelawu/BagchalGolang/internal/domain/game"
+)
+
+var (
+	ErrTicketNotFound = errors.New("ticket not found")
+	ErrAlreadyQueued  = errors.New("player already queued")
+	ErrInvalidSide    = errors.New("invalid side")
+	ErrNotWaiting     = errors.New("ticket is not waiting")
+	ErrNotTicketOwner = errors.New("ticket belongs to another player")
+)
+
+// DefaultRating 沒有積分來源時使用的預設積分
+const DefaultRating = 1500.0
+
+// GameCreator 負責為配對成功的玩家創建遊戲，由 game.GameService 實現
+type GameCreator interface {
+	CreateMatchedGame(tigerPlayerID, goatPlayerID string, timeControl *game.TimeControl, rated bool) (*game.Game, error)
+}
+
+// RatingProvider 提供玩家積分，side 為 Empty 時返回總積分
+type RatingProvider interface {
+	Rating(playerID string, side game.PieceType) float64
+}
+
+// Config 配對參數
+type Config struct {
+	InitialWindow         float64       // 初始可接受的積分差距
+	WindowGrowthPerSecond float64       // 每等待一秒放寬的積分差距
+	MaxWindow             float64       // 積分差距上限
+	TickInterval          time.Duration // 背景配對的間隔
+	ResolvedTTL           time.Duration // 已完成票據的保留時間，供長輪詢查詢
+	Rated                 bool          // 配對產生的遊戲是否計分
+}
+
+// DefaultConfig 返回預設配對參數
+func DefaultConfig() Config {
+	return Config{
+		InitialWindow:         100,
+		WindowGrowthPerSecond: 10,
+		MaxWindow:             800,
+		TickInterval:          time.Second,
+		ResolvedTTL:           5 * time.Minute,
+		Rated:                 true,
+	}
+}
+
+// Service 進程內配對服務
+type Service struct {
+	config  Config
+	games   GameCreator
+	ratings RatingProvider
+	players game.PlayerDirectory
+
+	mu       sync.Mutex
+	queue    []*Ticket          // 等待中的票據，按入隊時間排序
+	tickets  map[string]*Ticket // 所有票據（含已完成但未過期的）
+	byPlayer map[string]*Ticket // 玩家當前等待中的票據
+
+	stop chan struct{}
+	once sync.Once
+}
+
+func NewService(games GameCreator, ratings RatingProvider, players game.PlayerDirectory, config Config) *Service {
+	return &Service{
+		config:   config,
+		games:    games,
+		ratings:  ratings,
+		players:  players,
+		tickets:  make(map[string]*Ticket),
+		byPlayer: make(map[string]*Ticket),
+		stop:     make(chan struct{}),
+	}
+}
+
+// Start 啟動背景配對循環，隨等待時間放寬積分差距
+func (s *Service) Start() {
+	go func() {
+		ticker := time.NewTicker(s.config.TickInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				s.mu.Lock()
+				s.matchLocked(time.Now())
+				s.expireLocked(time.Now())
+				s.mu.Unlock()
+			case <-s.stop:
+				return
+			}
+		}
+	}()
+}
+
+// Stop 停止背景配對循環
+func (s *Service) Stop() {
+	s.once.Do(func() { close(s.stop) })
+}
+
+// Enqueue 將玩家加入配對隊列，若能立即配對則直接創建遊戲
+func (s *Service) Enqueue(playerID string, side game.PieceType, timeControl game.TimeControl) (*Ticket, error) {
+	if side != game.Empty && side != game.Tiger && side != game.Goat {
+		return nil, ErrInvalidSide
+	}
+
+	exists, err := s.players.Exists(playerID)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, game.ErrPlayerNotFound
+	}
+
+	rating := DefaultRating
+	if s.ratings != nil {
+		rating = s.ratings.Rating(playerID, side)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.byPlayer[playerID]; exists {
+		return nil, ErrAlreadyQueued
+	}
+
+	now := time.Now()
+	ticket := &Ticket{
+		ID:          generateTicketID(),
+		PlayerID:    playerID,
+		Side:        side,
+		TimeControl: timeControl,
+		Rating:      rating,
+		Status:      StatusWaiting,
+		EnqueuedAt:  now,
+		done:        make(chan struct{}),
+	}
+	s.queue = append(s.queue, ticket)
+	s.tickets[ticket.ID] = ticket
+	s.byPlayer[playerID] = ticket
+
+	s.matchLocked(now)
+	return s.snapshot(ticket), nil
+}
+
+// Cancel 代表玩家取消其等待中的票據
+func (s *Service) Cancel(ticketID, playerID string) (*Ticket, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ticket, exists := s.tickets[ticketID]
+	if !exists {
+		return nil, ErrTicketNotFound
+	}
+	if ticket.PlayerID != playerID {
+		return nil, ErrNotTicketOwner
+	}
+	if ticket.Status != StatusWaiting {
+		return nil, ErrNotWaiting
+	}
+
+	s.removeFromQueueLocked(ticket)
+	s.resolveLocked(ticket, StatusCancelled, time.Now())
+	return s.snapshot(ticket), nil
+}
+
+// Get 獲取票據當前狀態
+func (s *Service) Get(ticketID string) (*Ticket, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ticket, exists := s.tickets[ticketID]
+	if !exists {
+		return nil, ErrTicketNotFound
+	}
+	return s.snapshot(ticket), nil
+}
+
+// Wait 長輪詢：等待票據配對成功或取消，直到 ctx 結束或超時
+func (s *Service) Wait(ctx context.Context, ticketID string, timeout time.Duration) (*Ticket, error) {
+	s.mu.Lock()
+	ticket, exists := s.tickets[ticketID]
+	if !exists {
+		s.mu.Unlock()
+		return nil, ErrTicketNotFound
+	}
+	done := ticket.done
+	s.mu.Unlock()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case <-done:
+	case <-timer.C:
+	case <-ctx.Done():
+	}
+	return s.Get(ticketID)
+}
+
+// matchLocked 嘗試為隊列中的票據配對，調用前需持有鎖
+func (s *Service) matchLocked(now time.Time) {
+	for i := 0; i < len(s.queue); i++ {
+		a := s.queue[i]
+		best := -1
+		bestDiff := math.MaxFloat64
+		for j := i + 1; j < len(s.queue); j++ {
+			b := s.queue[j]
+			if !compatible(a, b) {
+				continue
+			}
+			diff := math.Abs(a.Rating - b.Rating)
+			// 雙方都必須接受當前的積分差距
+			if diff > a.Window(s.config, now) || diff > b.Window(s.config, now) {
+				continue
+			}
+			if diff < bestDiff {
+				best, bestDiff = j, diff
+			}
+		}
+		if best < 0 {
+			continue
+		}
+
+		b := s.queue[best]
+		tiger, goat := assignSides(a, b)
+		timeControl := a.TimeControl
+		newGame, err := s.games.CreateMatchedGame(tiger.PlayerID, goat.PlayerID, &timeControl, s.config.Rated)
+		if err != nil {
+			log.Printf("配對創建遊戲失敗: %v", err)
+			continue
+		}
+
+		tiger.GameID, goat.GameID = newGame.ID, newGame.ID
+		tiger.AssignedTo, goat.AssignedTo = game.Tiger, game.Goat
+		tiger.OpponentID, goat.OpponentID = goat.PlayerID, tiger.PlayerID
+
+		// 先移除較後的索引，避免前面的索引失效
+		s.queue = append(s.queue[:best], s.queue[best+1:]...)
+		s.queue = append(s.queue[:i], s.queue[i+1:]...)
+		s.resolveLocked(a, StatusMatched, now)
+		s.resolveLocked(b, StatusMatched, now)
+		i--
+	}
+}
+
+// expireLocked 清理過期的已完成票據，調用前需持有鎖
+func (s *Service) expireLocked(now time.Time) {
+	for id, ticket := range s.tickets {
+		if ticket.ResolvedAt != nil && now.Sub(*ticket.ResolvedAt) > s.config.ResolvedTTL {
+			delete(s.tickets, id)
+		}
+	}
+}
+
+// resolveLocked 將票據標記為完成並通知等待者，調用前需持有鎖
+func (s *Service) resolveLocked(ticket *Ticket, status TicketStatus, now time.Time) {
+	ticket.Status = status
+	ticket.ResolvedAt = &now
+	delete(s.byPlayer, ticket.PlayerID)
+	close(ticket.done)
+}
+
+// removeFromQueueLocked 從等待隊列中移除票據，調用前需持有鎖
+func (s *Service) removeFromQueueLocked(ticket *Ticket) {
+	for i, t := range s.queue {
+		if t == ticket {
+			s.queue = append(s.queue[:i], s.queue[i+1:]...)
+			return
+		}
+	}
+}
+
+// snapshot 返回票據的副本，避免調用方在鎖外讀取可變狀態
+func (s *Service) snapshot(ticket *Ticket) *Ticket {
+	copied := *ticket
+	copied.done = nil
+	return &copied
+}
+
+// compatible 檢查兩張票據的時間控制與陣營是否相容
+func compatible(a, b *Ticket) bool {
+	if a.PlayerID == b.PlayerID || a.TimeControl != b.TimeControl {
+		return false
+	}
+	return (a.accepts(game.Tiger) && b.accepts(game.Goat)) ||
+		(a.accepts(game.Goat) && b.accepts(game.Tiger))
+}
+
+// assignSides 為兩張相容的票據分配陣營，返回 (執虎方, 執羊方)
+func assignSides(a, b *Ticket) (*Ticket, *Ticket) {
+	switch {
+	case a.Side == game.Tiger || b.Side == game.Goat:
+		return a, b
+	case a.Side == game.Goat || b.Side == game.Tiger:
+		return b, a
+	}
+	// 雙方都不限陣營時隨機分配
+	if rand.Intn(2) == 0 {
+		return a, b
+	}
+	return b, a
+}
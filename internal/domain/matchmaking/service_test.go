@@ -0,0 +1,314 @@
+package matchmaking
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/nelawu/BagchalGolang/internal/domain/game"
+)
+
+// fakeGames 記錄配對創建的遊戲
+type fakeGames struct {
+	mu    sync.Mutex
+	games []*game.Game
+}
+
+func (f *fakeGames) CreateMatchedGame(tigerPlayerID, goatPlayerID string, timeControl *game.TimeControl, rated bool) (*game.Game, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	g := game.NewMatchedGame(tigerPlayerID, goatPlayerID, timeControl, rated)
+	f.games = append(f.games, g)
+	return g, nil
+}
+
+func (f *fakeGames) created() []*game.Game {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]*game.Game(nil), f.games...)
+}
+
+// fixedRatings 以玩家ID查表的積分，不在表中時為預設積分
+type fixedRatings map[string]float64
+
+func (r fixedRatings) Rating(playerID string, side game.PieceType) float64 {
+	if rating, ok := r[playerID]; ok {
+		return rating
+	}
+	return DefaultRating
+}
+
+// allPlayers 所有玩家都已註冊
+type allPlayers struct{}
+
+func (allPlayers) Exists(playerID string) (bool, error) { return true, nil }
+
+func newTestService(ratings fixedRatings) (*Service, *fakeGames) {
+	games := &fakeGames{}
+	return NewService(games, ratings, allPlayers{}, DefaultConfig()), games
+}
+
+var blitz = game.TimeControl{InitialSeconds: 300, IncrementSeconds: 5}
+
+// matchAt 以指定時間執行一次背景配對
+func (s *Service) matchAt(now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.matchLocked(now)
+}
+
+func TestRatingWindow(t *testing.T) {
+	tests := []struct {
+		name    string
+		a, b    float64
+		waited  time.Duration
+		matched bool
+	}{
+		{"within initial window", 1500, 1590, 0, true},
+		{"outside initial window", 1500, 1800, 0, false},
+		{"window not wide enough yet", 1500, 1800, 10 * time.Second, false},
+		{"window widened", 1500, 1800, 21 * time.Second, true},
+		{"beyond the maximum window", 1500, 2400, time.Hour, false},
+		{"at the maximum window", 1500, 2300, time.Hour, true},
+	}
+	for _, tt := range tests {
+		s, games := newTestService(fixedRatings{"a": tt.a, "b": tt.b})
+		a, err := s.Enqueue("a", game.Empty, blitz)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, err := s.Enqueue("b", game.Empty, blitz)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if tt.waited > 0 {
+			s.matchAt(b.EnqueuedAt.Add(tt.waited))
+		}
+
+		a, _ = s.Get(a.ID)
+		b, _ = s.Get(b.ID)
+		if matched := a.Status == StatusMatched; matched != tt.matched {
+			t.Errorf("%s: matched = %v, want %v", tt.name, matched, tt.matched)
+			continue
+		}
+		if !tt.matched {
+			if a.Status != StatusWaiting || b.Status != StatusWaiting || len(games.created()) != 0 {
+				t.Errorf("%s: unmatched tickets %s/%s, %d games", tt.name, a.Status, b.Status, len(games.created()))
+			}
+			continue
+		}
+		if b.Status != StatusMatched || a.GameID == "" || a.GameID != b.GameID || a.OpponentID != "b" || b.OpponentID != "a" {
+			t.Errorf("%s: tickets %+v and %+v", tt.name, a, b)
+		}
+		if a.AssignedTo == b.AssignedTo || a.AssignedTo == game.Empty {
+			t.Errorf("%s: sides %d and %d", tt.name, a.AssignedTo, b.AssignedTo)
+		}
+	}
+}
+
+func TestClosestRatingWins(t *testing.T) {
+	s, _ := newTestService(fixedRatings{"first": 1500, "far": 1750, "near": 1650})
+	first, err := s.Enqueue("first", game.Goat, blitz)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, id := range []string{"far", "near"} {
+		if _, err := s.Enqueue(id, game.Tiger, blitz); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// 等待 20 秒後雙方的積分差距都在範圍內，選擇差距較小的一方
+	s.matchAt(first.EnqueuedAt.Add(20 * time.Second))
+	if first, _ = s.Get(first.ID); first.Status != StatusMatched || first.OpponentID != "near" {
+		t.Errorf("matched %q (%s), want near", first.OpponentID, first.Status)
+	}
+}
+
+func TestCompatibility(t *testing.T) {
+	rapid := game.TimeControl{InitialSeconds: 600}
+	tests := []struct {
+		name         string
+		sideA, sideB game.PieceType
+		tcB          game.TimeControl
+		matched      bool
+		aGets        game.PieceType // Empty 表示隨機
+	}{
+		{"tiger and goat", game.Tiger, game.Goat, blitz, true, game.Tiger},
+		{"goat and tiger", game.Goat, game.Tiger, blitz, true, game.Goat},
+		{"tiger and any", game.Tiger, game.Empty, blitz, true, game.Tiger},
+		{"any and tiger", game.Empty, game.Tiger, blitz, true, game.Goat},
+		{"any and goat", game.Empty, game.Goat, blitz, true, game.Tiger},
+		{"any and any", game.Empty, game.Empty, blitz, true, game.Empty},
+		{"both tiger", game.Tiger, game.Tiger, blitz, false, game.Empty},
+		{"both goat", game.Goat, game.Goat, blitz, false, game.Empty},
+		{"different time control", game.Empty, game.Empty, rapid, false, game.Empty},
+		{"untimed and timed", game.Empty, game.Empty, game.TimeControl{}, false, game.Empty},
+	}
+	for _, tt := range tests {
+		s, games := newTestService(nil)
+		if _, err := s.Enqueue("a", tt.sideA, blitz); err != nil {
+			t.Fatal(err)
+		}
+		b, err := s.Enqueue("b", tt.sideB, tt.tcB)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if matched := b.Status == StatusMatched; matched != tt.matched {
+			t.Errorf("%s: matched = %v, want %v", tt.name, matched, tt.matched)
+			continue
+		}
+		if !tt.matched {
+			continue
+		}
+		g := games.created()[0]
+		if tt.aGets != game.Empty && g.SideOf("a") != tt.aGets {
+			t.Errorf("%s: a plays %d, want %d", tt.name, g.SideOf("a"), tt.aGets)
+		}
+		if g.TimeControl == nil || *g.TimeControl != blitz || !g.Rated {
+			t.Errorf("%s: game time control %v rated %v", tt.name, g.TimeControl, g.Rated)
+		}
+	}
+}
+
+func TestEnqueueErrors(t *testing.T) {
+	s, _ := newTestService(nil)
+	if _, err := s.Enqueue("a", game.PieceType(7), blitz); err != ErrInvalidSide {
+		t.Errorf("invalid side: err = %v", err)
+	}
+	if _, err := s.Enqueue("a", game.Tiger, blitz); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Enqueue("a", game.Goat, blitz); err != ErrAlreadyQueued {
+		t.Errorf("second ticket: err = %v, want ErrAlreadyQueued", err)
+	}
+}
+
+// TestCancelRacingMatch 取消與對手入隊同時發生時，票據只能是取消或配對其中之一
+func TestCancelRacingMatch(t *testing.T) {
+	for i := 0; i < 200; i++ {
+		s, games := newTestService(nil)
+		a, err := s.Enqueue("a", game.Empty, blitz)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var wg sync.WaitGroup
+		var cancelErr error
+		var waited *Ticket
+		wg.Add(3)
+		go func() {
+			defer wg.Done()
+			_, cancelErr = s.Cancel(a.ID, "a")
+		}()
+		go func() {
+			defer wg.Done()
+			if _, err := s.Enqueue("b", game.Empty, blitz); err != nil {
+				t.Error(err)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			waited, _ = s.Wait(context.Background(), a.ID, time.Second)
+		}()
+		wg.Wait()
+
+		final, _ := s.Get(a.ID)
+		switch {
+		case cancelErr == nil:
+			if final.Status != StatusCancelled || len(games.created()) != 0 {
+				t.Fatalf("cancelled ticket is %s with %d games", final.Status, len(games.created()))
+			}
+		case cancelErr == ErrNotWaiting:
+			if final.Status != StatusMatched || len(games.created()) != 1 || final.GameID != games.created()[0].ID {
+				t.Fatalf("matched ticket is %s with %d games", final.Status, len(games.created()))
+			}
+		default:
+			t.Fatalf("cancel: %v", cancelErr)
+		}
+		if waited == nil || waited.Status != final.Status {
+			t.Fatalf("Wait returned %+v, want status %s", waited, final.Status)
+		}
+	}
+}
+
+// TestConcurrentEnqueue 並發入隊時每位玩家最多被配對一次，遊戲數與配對數一致
+func TestConcurrentEnqueue(t *testing.T) {
+	const players = 40
+	s, games := newTestService(nil)
+
+	var wg sync.WaitGroup
+	tickets := make([]*Ticket, players)
+	for i := 0; i < players; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			side := []game.PieceType{game.Empty, game.Tiger, game.Goat}[i%3]
+			ticket, err := s.Enqueue(fmt.Sprintf("p%d", i), side, blitz)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			tickets[i] = ticket
+		}(i)
+	}
+	wg.Wait()
+
+	inGame := map[string]string{}
+	for _, g := range games.created() {
+		for _, id := range []string{g.TigerPlayerID, g.GoatPlayerID} {
+			if _, dup := inGame[id]; dup {
+				t.Fatalf("%s matched twice", id)
+			}
+			inGame[id] = g.ID
+		}
+	}
+	waiting := 0
+	for _, ticket := range tickets {
+		final, err := s.Get(ticket.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		switch final.Status {
+		case StatusMatched:
+			if inGame[final.PlayerID] != final.GameID {
+				t.Errorf("%s: ticket game %s not created for the player", final.PlayerID, final.GameID)
+			}
+		case StatusWaiting:
+			waiting++
+			if _, ok := inGame[final.PlayerID]; ok {
+				t.Errorf("%s: waiting but already in a game", final.PlayerID)
+			}
+		default:
+			t.Errorf("%s: status %s", final.PlayerID, final.Status)
+		}
+	}
+	if waiting+2*len(games.created()) != players {
+		t.Errorf("%d waiting and %d games for %d players", waiting, len(games.created()), players)
+	}
+	// 同一積分的玩家只有陣營衝突時才會留在隊列中
+	if waiting > players/3 {
+		t.Errorf("%d players left waiting", waiting)
+	}
+
+	// 同一玩家並發入隊只有一張票據成功
+	var solo sync.WaitGroup
+	var mu sync.Mutex
+	succeeded := 0
+	for i := 0; i < 10; i++ {
+		solo.Add(1)
+		go func() {
+			defer solo.Done()
+			if _, err := s.Enqueue("solo", game.Tiger, game.TimeControl{InitialSeconds: 60}); err == nil {
+				mu.Lock()
+				succeeded++
+				mu.Unlock()
+			}
+		}()
+	}
+	solo.Wait()
+	if succeeded != 1 {
+		t.Errorf("%d concurrent tickets for one player", succeeded)
+	}
+}
@@ -0,0 +1,96 @@
+package player
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"time"
+
+	"github.com/nelawu/BagchalGolang/internal/domain/game"
+)
+
+// BoardOrientation 表示棋盤顯示方向
+type BoardOrientation string
+
+const (
+	OrientationNormal  BoardOrientation = "normal"
+	OrientationFlipped BoardOrientation = "flipped"
+)
+
+// Preferences 表示玩家偏好設置
+type Preferences struct {
+	DefaultSide      game.PieceType   `json:"defaultSide"`      // 預設陣營，0 表示不限
+	AILevel          int              `json:"aiLevel"`          // 預設AI難度
+	BoardOrientation BoardOrientation `json:"boardOrientation"` // 棋盤方向
+}
+
+// DefaultPreferences 返回預設偏好設置
+func DefaultPreferences() Preferences {
+	return Preferences{
+		DefaultSide:      game.Empty,
+		AILevel:          2,
+		BoardOrientation: OrientationNormal,
+	}
+}
+
+// Player 表示一位註冊玩家
+type Player struct {
+	ID          string      `json:"id"`
+	Username    string      `json:"username"`    // 唯一用戶名，用於登入與查找
+	DisplayName string      `json:"displayName"` // 顯示名稱
+	Preferences Preferences `json:"preferences"`
+	APIKeyHash  string      `json:"-"` // API密鑰的 SHA-256 摘要，明文僅在註冊時返回一次
+	CreatedAt   time.Time   `json:"createdAt"`
+	UpdatedAt   time.Time   `json:"updatedAt"`
+}
+
+// Clone 返回玩家資料的副本，存儲中的玩家可能正被其他請求讀取，更新時需先複製
+func (p *Player) Clone() *Player {
+	clone := *p
+	return &clone
+}
+
+// NewPlayer 創建一位新玩家
+func NewPlayer(username, displayName string) *Player {
+	if displayName == "" {
+		displayName = username
+	}
+	return &Player{
+		ID:          generatePlayerID(),
+		Username:    username,
+		DisplayName: displayName,
+		Preferences: DefaultPreferences(),
+		CreatedAt:   time.Now(),
+		UpdatedAt:   time.Now(),
+	}
+}
+
+// generatePlayerID 生成玩家ID
+func generatePlayerID() string {
+	suffix := make([]byte, 8)
+	if _, err := rand.Read(suffix); err != nil {
+		return "player_" + time.Now().Format("20060102150405.000000000")
+	}
+	return "player_" + hex.EncodeToString(suffix)
+}
+
+// generateAPIKey 生成API密鑰
+func generateAPIKey() (string, error) {
+	key := make([]byte, 24)
+	if _, err := rand.Read(key); err != nil {
+		return "", err
+	}
+	return "bk_" + hex.EncodeToString(key), nil
+}
+
+// hashAPIKey 返回API密鑰的摘要
+func hashAPIKey(apiKey string) string {
+	sum := sha256.Sum256([]byte(apiKey))
+	return hex.EncodeToString(sum[:])
+}
+
+// CheckAPIKey 以常數時間比對API密鑰
+func (p *Player) CheckAPIKey(apiKey string) bool {
+	return subtle.ConstantTimeCompare([]byte(hashAPIKey(apiKey)), []byte(p.APIKeyHash)) == 1
+}
@@ -0,0 +1,13 @@
+package player
+
+// PlayerRepository 定義玩家資料存儲介面
+type PlayerRepository interface {
+	// Save 保存玩家資料，用戶名已被其他玩家使用時返回 ErrUsernameTaken
+	Save(player *Player) error
+
+	// GetByID 根據ID獲取玩家
+	GetByID(id string) (*Player, error)
+
+	// GetByUsername 根據用戶名獲取玩家
+	GetByUsername(username string) (*Player, error)
+}
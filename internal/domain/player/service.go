@@ -0,0 +1,141 @@
+package player
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/nelawu/BagchalGolang/internal/domain/game"
+)
+
+var (
+	ErrPlayerNotFound     = errors.New("player not found")
+	ErrUsernameTaken      = errors.New("username already taken")
+	ErrInvalidUsername    = errors.New("invalid username")
+	ErrInvalidDisplayName = errors.New("invalid display name")
+	ErrInvalidPreferences = errors.New("invalid preferences")
+	ErrInvalidCredentials = errors.New("invalid credentials")
+)
+
+const maxDisplayNameLength = 32
+
+var usernamePattern = regexp.MustCompile(`^[a-zA-Z0-9_]{3,20}$`)
+
+type PlayerService struct {
+	repository PlayerRepository
+}
+
+func NewPlayerService(repository PlayerRepository) *PlayerService {
+	return &PlayerService{
+		repository: repository,
+	}
+}
+
+// Register 註冊新玩家，返回玩家資料及API密鑰（僅此一次返回明文）
+func (s *PlayerService) Register(username, displayName string) (*Player, string, error) {
+	if !usernamePattern.MatchString(username) {
+		return nil, "", ErrInvalidUsername
+	}
+	displayName = strings.TrimSpace(displayName)
+	if utf8.RuneCountInString(displayName) > maxDisplayNameLength {
+		return nil, "", ErrInvalidDisplayName
+	}
+
+	// 用戶名不區分大小寫，此處先行檢查，並發註冊由存儲在保存時保證唯一
+	username = strings.ToLower(username)
+	if _, err := s.repository.GetByUsername(username); err == nil {
+		return nil, "", ErrUsernameTaken
+	} else if err != ErrPlayerNotFound {
+		return nil, "", err
+	}
+
+	apiKey, err := generateAPIKey()
+	if err != nil {
+		return nil, "", err
+	}
+
+	player := NewPlayer(username, displayName)
+	player.APIKeyHash = hashAPIKey(apiKey)
+	if err := s.repository.Save(player); err != nil {
+		return nil, "", err
+	}
+	return player, apiKey, nil
+}
+
+// Authenticate 使用用戶名與API密鑰驗證玩家身份
+func (s *PlayerService) Authenticate(username, apiKey string) (*Player, error) {
+	player, err := s.repository.GetByUsername(strings.ToLower(username))
+	if err == ErrPlayerNotFound {
+		return nil, ErrInvalidCredentials
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !player.CheckAPIKey(apiKey) {
+		return nil, ErrInvalidCredentials
+	}
+	return player, nil
+}
+
+// GetPlayer 根據ID獲取玩家
+func (s *PlayerService) GetPlayer(id string) (*Player, error) {
+	return s.repository.GetByID(id)
+}
+
+// UpdateProfile 更新顯示名稱與偏好設置，displayName 為空時保留原值
+func (s *PlayerService) UpdateProfile(id, displayName string, preferences Preferences) (*Player, error) {
+	stored, err := s.repository.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	displayName = strings.TrimSpace(displayName)
+	if utf8.RuneCountInString(displayName) > maxDisplayNameLength {
+		return nil, ErrInvalidDisplayName
+	}
+	if !validPreferences(preferences) {
+		return nil, ErrInvalidPreferences
+	}
+
+	// 在副本上修改後保存，不改動其他請求可能正在讀取的存儲對象
+	player := stored.Clone()
+	if displayName != "" {
+		player.DisplayName = displayName
+	}
+	player.Preferences = preferences
+	player.UpdatedAt = time.Now()
+
+	if err := s.repository.Save(player); err != nil {
+		return nil, err
+	}
+	return player, nil
+}
+
+// Exists 檢查玩家是否存在，實現 game.PlayerDirectory
+func (s *PlayerService) Exists(id string) (bool, error) {
+	_, err := s.repository.GetByID(id)
+	if err == ErrPlayerNotFound {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// validPreferences 檢查偏好設置是否合法
+func validPreferences(preferences Preferences) bool {
+	switch preferences.DefaultSide {
+	case game.Empty, game.Tiger, game.Goat:
+	default:
+		return false
+	}
+	if preferences.AILevel < 1 || preferences.AILevel > 3 {
+		return false
+	}
+	switch preferences.BoardOrientation {
+	case OrientationNormal, OrientationFlipped:
+	default:
+		return false
+	}
+	return true
+}
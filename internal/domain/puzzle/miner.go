@@ -0,0 +1,106 @@
+package puzzle
+
+import (
+	"sort"
+	"time"
+
+	"github.com/nelawu/BagchalGolang/internal/ai"
+	"github.com/nelawu/BagchalGolang/internal/domain/analysis"
+	"github.com/nelawu/BagchalGolang/internal/domain/game"
+)
+
+// 挖掘參數
+const (
+	mineDepth    = 2                      // 評估每個候選移動時的搜索深度
+	mineMoveTime = 200 * time.Millisecond // 每個候選移動的時間上限
+	minScoreGap  = 150                    // 最佳著法至少領先次佳著法的分數
+	winningScore = ai.WinScore - 1000     // 超過此分數視為必勝
+
+	maxSelfPlayPlies = 200 // 自我對弈的最大步數
+)
+
+// scoredMove 帶評估分數的候選移動
+type scoredMove struct {
+	move  game.Move
+	score int // 走棋一方視角
+}
+
+// minePosition 檢查局面是否只有一步明顯最佳的著法，是則返回謎題
+func (s *PuzzleService) minePosition(state game.GameState, source Source) *Puzzle {
+	if state.IsGameOver {
+		return nil
+	}
+	moves := game.LegalMoves(&state)
+	if len(moves) < 2 {
+		return nil
+	}
+
+	side := state.CurrentTurn
+	candidates := make([]scoredMove, 0, len(moves))
+	for _, move := range moves {
+		child := state
+		game.ApplyMove(&child, move)
+		result := s.engine.Analyze(child, ai.Limits{Depth: mineDepth, MoveTime: mineMoveTime})
+		score := result.TigerScore
+		if side == game.Goat {
+			score = result.GoatScore
+		}
+		candidates = append(candidates, scoredMove{move: move, score: score})
+	}
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].score > candidates[j].score })
+
+	best, second := candidates[0], candidates[1]
+	gap := best.score - second.score
+	if gap < minScoreGap {
+		return nil
+	}
+
+	themes := puzzleThemes(&state, best, second)
+	if themes == nil {
+		return nil
+	}
+	key, _ := game.CanonicalKey(&state)
+	return &Puzzle{
+		ID:        generatePuzzleID(),
+		State:     state,
+		Side:      side,
+		Solution:  best.move,
+		Themes:    themes,
+		Rating:    initialRating(len(moves), gap),
+		Source:    source,
+		CreatedAt: time.Now(),
+		Key:       key,
+	}
+}
+
+// puzzleThemes 返回謎題主題，不屬於戰術主題時返回 nil
+// 虎方需要吃子，羊方需要擋住吃子或困住虎，唯一的致勝著法對雙方都算
+func puzzleThemes(state *game.GameState, best, second scoredMove) []Theme {
+	var themes []Theme
+	switch analysis.ClassifyMove(state, best.move) {
+	case analysis.ReasonCaptures:
+		themes = append(themes, ThemeCapture)
+	case analysis.ReasonBlocksCapture:
+		themes = append(themes, ThemeBlockCapture)
+	case analysis.ReasonTrapsTiger:
+		themes = append(themes, ThemeTrapTiger)
+	}
+	if best.score >= winningScore && second.score < winningScore {
+		themes = append(themes, ThemeWinning)
+	}
+	if themes == nil {
+		return nil
+	}
+
+	if state.GoatsInHand > 0 {
+		return append(themes, ThemePlacement)
+	}
+	return append(themes, ThemeMovement)
+}
+
+// initialRating 根據候選移動數與最佳著法的領先幅度估計初始難度
+// 候選越多越難找到答案，領先越大越容易看出
+func initialRating(candidates, gap int) float64 {
+	rating := defaultRating + 10*float64(candidates-10) - float64(min(gap, 1000)-minScoreGap)/2
+	return max(minRating, min(maxRating, rating))
+}
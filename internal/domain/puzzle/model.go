@@ -0,0 +1,82 @@
+package puzzle
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+
+	"github.com/nelawu/BagchalGolang/internal/domain/game"
+)
+
+// Theme 表示謎題的主題
+type Theme string
+
+const (
+	ThemeCapture      Theme = "captures"       // 虎吃子
+	ThemeBlockCapture Theme = "blocks_capture" // 羊擋住吃子
+	ThemeTrapTiger    Theme = "traps_tiger"    // 羊困住虎
+	ThemeWinning      Theme = "winning"        // 唯一的致勝著法
+	ThemePlacement    Theme = "placement"      // 放置階段
+	ThemeMovement     Theme = "movement"       // 移動階段
+)
+
+// Source 記錄謎題來源的局面
+type Source struct {
+	GameID string `json:"gameId,omitempty"` // 空字串表示來自引擎自我對弈
+	Ply    int    `json:"ply"`
+}
+
+// Puzzle 表示一道只有一步明顯最佳著法的戰術謎題
+type Puzzle struct {
+	ID        string         `json:"id"`
+	State     game.GameState `json:"state"`
+	Side      game.PieceType `json:"side"`     // 解題方
+	Solution  game.Move      `json:"-"`        // 答案，只在作答後公開
+	Themes    []Theme        `json:"themes"`   // 主題
+	Rating    float64        `json:"rating"`   // 難度積分，隨作答結果調整
+	Source    Source         `json:"-"`        // 來源對局可查出答案，只在作答後公開
+	Attempts  int            `json:"attempts"` // 首次作答的玩家數
+	Solves    int            `json:"solves"`   // 首次作答即答對的玩家數
+	CreatedAt time.Time      `json:"createdAt"`
+
+	// Key 標識局面，對稱的局面相同，用於去重
+	Key string `json:"-"`
+}
+
+// HasTheme 檢查謎題是否包含指定主題
+func (p *Puzzle) HasTheme(theme Theme) bool {
+	for _, t := range p.Themes {
+		if t == theme {
+			return true
+		}
+	}
+	return false
+}
+
+// Attempt 記錄一位玩家對一道謎題的作答，只有首次作答影響謎題難度
+type Attempt struct {
+	PuzzleID   string    `json:"puzzleId"`
+	PlayerID   string    `json:"playerId"`
+	Correct    bool      `json:"correct"` // 首次作答是否正確
+	Tries      int       `json:"tries"`   // 作答次數
+	FirstTryAt time.Time `json:"firstTryAt"`
+	LastTryAt  time.Time `json:"lastTryAt"`
+}
+
+// AttemptResult 表示一次作答的結果
+type AttemptResult struct {
+	Correct  bool      `json:"correct"`
+	Solution game.Move `json:"solution"`
+	Source   Source    `json:"source"`
+	Rating   float64   `json:"rating"`  // 作答後的謎題難度
+	Counted  bool      `json:"counted"` // 是否為首次作答並計入謎題難度
+}
+
+// generatePuzzleID 生成謎題ID
+func generatePuzzleID() string {
+	suffix := make([]byte, 6)
+	if _, err := rand.Read(suffix); err != nil {
+		return "puzzle_" + time.Now().Format("20060102150405.000000000")
+	}
+	return "puzzle_" + hex.EncodeToString(suffix)
+}
@@ -0,0 +1,37 @@
+package puzzle
+
+import "errors"
+
+var (
+	ErrPuzzleNotFound  = errors.New("puzzle not found")
+	ErrAttemptNotFound = errors.New("attempt not found")
+)
+
+// Filter 謎題查詢條件，零值表示不限
+type Filter struct {
+	Theme     Theme
+	MinRating float64
+	MaxRating float64
+	Limit     int
+}
+
+// PuzzleRepository 定義謎題資料存儲介面
+type PuzzleRepository interface {
+	// Save 保存謎題
+	Save(puzzle *Puzzle) error
+
+	// GetByID 根據ID獲取謎題
+	GetByID(id string) (*Puzzle, error)
+
+	// GetByKey 根據局面標識獲取謎題
+	GetByKey(key string) (*Puzzle, error)
+
+	// List 按條件列出謎題，按難度排序
+	List(filter Filter) ([]*Puzzle, error)
+
+	// SaveAttempt 保存玩家的作答記錄
+	SaveAttempt(attempt *Attempt) error
+
+	// GetAttempt 獲取玩家對謎題的作答記錄
+	GetAttempt(puzzleID, playerID string) (*Attempt, error)
+}
@@ -0,0 +1,259 @@
+package puzzle
+
+import (
+	"errors"
+	"log"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/nelawu/BagchalGolang/internal/domain/analysis"
+	"github.com/nelawu/BagchalGolang/internal/domain/game"
+)
+
+var (
+	ErrInvalidMove     = errors.New("move is not legal in this position")
+	ErrQueueFull       = errors.New("puzzle mining queue is full")
+	ErrInvalidSelfPlay = errors.New("invalid number of self-play games")
+)
+
+// 難度積分參數
+const (
+	defaultRating = 1500.0
+	minRating     = 600.0
+	maxRating     = 2600.0
+	ratingK       = 16.0 // 每次作答的積分調整幅度
+
+	// MaxSelfPlayGames 單次請求允許的自我對弈局數
+	MaxSelfPlayGames = 20
+
+	// miningQueueSize 等待挖掘的任務數上限
+	miningQueueSize = 64
+)
+
+// mineJob 挖掘任務：已結束遊戲的移動記錄，或一局自我對弈
+type mineJob struct {
+	gameID   string
+	history  []game.Move
+	selfPlay bool
+}
+
+type PuzzleService struct {
+	repository PuzzleRepository
+	engine     analysis.Analyzer
+	player     game.AIEngine // 自我對弈時選擇移動
+
+	mu   sync.Mutex // 保護謎題統計及去重
+	jobs chan mineJob
+	stop chan struct{}
+	once sync.Once
+}
+
+func NewPuzzleService(repository PuzzleRepository, engine analysis.Analyzer, player game.AIEngine) *PuzzleService {
+	return &PuzzleService{
+		repository: repository,
+		engine:     engine,
+		player:     player,
+		jobs:       make(chan mineJob, miningQueueSize),
+		stop:       make(chan struct{}),
+	}
+}
+
+// Start 啟動背景挖掘循環
+func (s *PuzzleService) Start() {
+	go func() {
+		for {
+			select {
+			case job := <-s.jobs:
+				var found int
+				var err error
+				if job.selfPlay {
+					found, err = s.MineSelfPlay()
+				} else {
+					found, err = s.MineGame(job.gameID, job.history)
+				}
+				if err != nil {
+					log.Printf("挖掘謎題失敗: %v", err)
+				} else if found > 0 {
+					log.Printf("挖掘到 %d 道謎題", found)
+				}
+			case <-s.stop:
+				return
+			}
+		}
+	}()
+}
+
+// Stop 停止背景挖掘循環
+func (s *PuzzleService) Stop() {
+	s.once.Do(func() { close(s.stop) })
+}
+
+// HandleGameOver 將已結束的遊戲加入挖掘隊列
+func (s *PuzzleService) HandleGameOver(g *game.Game) {
+	job := mineJob{gameID: g.ID, history: append([]game.Move(nil), g.History...)}
+	select {
+	case s.jobs <- job:
+	default:
+		log.Printf("謎題挖掘隊列已滿，跳過遊戲 %s", g.ID)
+	}
+}
+
+// RequestSelfPlay 將指定局數的引擎自我對弈加入挖掘隊列
+func (s *PuzzleService) RequestSelfPlay(games int) error {
+	if games < 1 || games > MaxSelfPlayGames {
+		return ErrInvalidSelfPlay
+	}
+	for i := 0; i < games; i++ {
+		select {
+		case s.jobs <- mineJob{selfPlay: true}:
+		default:
+			return ErrQueueFull
+		}
+	}
+	return nil
+}
+
+// MineGame 重放遊戲並從每個局面挖掘謎題，返回新增的謎題數
+func (s *PuzzleService) MineGame(gameID string, history []game.Move) (int, error) {
+	found := 0
+	state := game.NewGameState()
+	for ply, move := range history {
+		added, err := s.addPuzzle(s.minePosition(state, Source{GameID: gameID, Ply: ply}))
+		if err != nil {
+			return found, err
+		}
+		if added {
+			found++
+		}
+		game.ApplyMove(&state, move)
+	}
+	return found, nil
+}
+
+// MineSelfPlay 進行一局引擎自我對弈並挖掘謎題，返回新增的謎題數
+func (s *PuzzleService) MineSelfPlay() (int, error) {
+	found := 0
+	g := game.NewMatchedGame(game.AIPlayerID(2), game.AIPlayerID(2), nil, false)
+	for ply := 0; ply < maxSelfPlayPlies && !g.State.IsGameOver; ply++ {
+		added, err := s.addPuzzle(s.minePosition(g.State, Source{Ply: ply}))
+		if err != nil {
+			return found, err
+		}
+		if added {
+			found++
+		}
+
+		move, err := s.player.CalculateNextMove(g)
+		if err != nil {
+			return found, err
+		}
+		if move == nil {
+			break
+		}
+		if err := g.MakeMove(*move); err != nil {
+			return found, err
+		}
+	}
+	return found, nil
+}
+
+// addPuzzle 保存尚未收錄的謎題
+func (s *PuzzleService) addPuzzle(p *Puzzle) (bool, error) {
+	if p == nil {
+		return false, nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := s.repository.GetByKey(p.Key); err == nil {
+		return false, nil
+	}
+	if err := s.repository.Save(p); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// GetPuzzle 獲取謎題
+func (s *PuzzleService) GetPuzzle(id string) (*Puzzle, error) {
+	return s.repository.GetByID(id)
+}
+
+// ListPuzzles 按條件列出謎題
+func (s *PuzzleService) ListPuzzles(filter Filter) ([]*Puzzle, error) {
+	return s.repository.List(filter)
+}
+
+// Attempt 驗證玩家的作答，首次作答按結果調整謎題難度：答對降低難度，答錯提高難度
+// 重複作答只返回結果，避免反覆答錯抬高難度
+func (s *PuzzleService) Attempt(id, playerID string, move game.Move) (*AttemptResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p, err := s.repository.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	state := p.State
+	legal, ok := game.FindLegalMove(&state, move)
+	if !ok {
+		return nil, ErrInvalidMove
+	}
+	correct := legal.From == p.Solution.From && legal.To == p.Solution.To
+
+	now := time.Now()
+	attempt, err := s.repository.GetAttempt(id, playerID)
+	switch err {
+	case nil:
+		attempt.Tries++
+		attempt.LastTryAt = now
+		if err := s.repository.SaveAttempt(attempt); err != nil {
+			return nil, err
+		}
+		return &AttemptResult{
+			Correct:  correct,
+			Solution: p.Solution,
+			Source:   p.Source,
+			Rating:   p.Rating,
+		}, nil
+	case ErrAttemptNotFound:
+	default:
+		return nil, err
+	}
+
+	// 以預設積分的解題者為對手計算期望答對率
+	expected := 1 / (1 + math.Pow(10, (p.Rating-defaultRating)/400))
+	if correct {
+		p.Solves++
+		p.Rating -= ratingK * (1 - expected)
+	} else {
+		p.Rating += ratingK * expected
+	}
+	p.Rating = max(minRating, min(maxRating, p.Rating))
+	p.Attempts++
+	if err := s.repository.Save(p); err != nil {
+		return nil, err
+	}
+	attempt = &Attempt{
+		PuzzleID:   id,
+		PlayerID:   playerID,
+		Correct:    correct,
+		Tries:      1,
+		FirstTryAt: now,
+		LastTryAt:  now,
+	}
+	if err := s.repository.SaveAttempt(attempt); err != nil {
+		return nil, err
+	}
+
+	return &AttemptResult{
+		Correct:  correct,
+		Solution: p.Solution,
+		Source:   p.Source,
+		Rating:   p.Rating,
+		Counted:  true,
+	}, nil
+}
@@ -0,0 +1,128 @@
+package rating
+
+import (
+	"math"
+)
+
+// Glicko-2 常數，參見 Glickman, "Example of the Glicko-2 system"
+const (
+	glickoScale       = 173.7178
+	DefaultRating     = 1500.0
+	DefaultDeviation  = 350.0
+	DefaultVolatility = 0.06
+	MinDeviation      = 30.0
+	tau               = 0.5      // 系統常數，限制波動率的變化幅度
+	convergence       = 0.000001 // 波動率迭代的收斂閾值
+)
+
+// Glicko 表示一個 Glicko-2 積分
+type Glicko struct {
+	Rating     float64 `json:"rating"`
+	Deviation  float64 `json:"deviation"`  // 積分偏差（RD）
+	Volatility float64 `json:"volatility"` // 波動率
+	Games      int     `json:"games"`      // 計分對局數
+}
+
+// NewGlicko 返回初始積分
+func NewGlicko() Glicko {
+	return Glicko{
+		Rating:     DefaultRating,
+		Deviation:  DefaultDeviation,
+		Volatility: DefaultVolatility,
+	}
+}
+
+// Result 表示一局對局結果
+type Result struct {
+	Opponent Glicko
+	Score    float64 // 1 勝，0.5 和，0 負
+}
+
+// Update 根據一個評分周期內的對局結果計算新積分
+func (r Glicko) Update(results []Result) Glicko {
+	mu := (r.Rating - DefaultRating) / glickoScale
+	phi := r.Deviation / glickoScale
+
+	if len(results) == 0 {
+		// 沒有對局時僅增加偏差
+		phi = math.Sqrt(phi*phi + r.Volatility*r.Volatility)
+		r.Deviation = math.Min(phi*glickoScale, DefaultDeviation)
+		return r
+	}
+
+	var vInv, deltaSum float64
+	for _, result := range results {
+		muJ := (result.Opponent.Rating - DefaultRating) / glickoScale
+		phiJ := result.Opponent.Deviation / glickoScale
+		g := gFactor(phiJ)
+		e := expectedScore(mu, muJ, g)
+		vInv += g * g * e * (1 - e)
+		deltaSum += g * (result.Score - e)
+	}
+	v := 1 / vInv
+	delta := v * deltaSum
+
+	sigma := newVolatility(phi, r.Volatility, v, delta)
+
+	phiStar := math.Sqrt(phi*phi + sigma*sigma)
+	newPhi := 1 / math.Sqrt(1/(phiStar*phiStar)+1/v)
+	newMu := mu + newPhi*newPhi*deltaSum
+
+	return Glicko{
+		Rating:     newMu*glickoScale + DefaultRating,
+		Deviation:  math.Max(newPhi*glickoScale, MinDeviation),
+		Volatility: sigma,
+		Games:      r.Games + len(results),
+	}
+}
+
+// ExpectedScore 返回對陣指定對手的期望得分
+func (r Glicko) ExpectedScore(opponent Glicko) float64 {
+	mu := (r.Rating - DefaultRating) / glickoScale
+	muJ := (opponent.Rating - DefaultRating) / glickoScale
+	return expectedScore(mu, muJ, gFactor(opponent.Deviation/glickoScale))
+}
+
+func gFactor(phi float64) float64 {
+	return 1 / math.Sqrt(1+3*phi*phi/(math.Pi*math.Pi))
+}
+
+func expectedScore(mu, muJ, g float64) float64 {
+	return 1 / (1 + math.Exp(-g*(mu-muJ)))
+}
+
+// newVolatility 使用 Illinois 算法求解新的波動率
+func newVolatility(phi, sigma, v, delta float64) float64 {
+	a := math.Log(sigma * sigma)
+	f := func(x float64) float64 {
+		ex := math.Exp(x)
+		num := ex * (delta*delta - phi*phi - v - ex)
+		den := 2 * math.Pow(phi*phi+v+ex, 2)
+		return num/den - (x-a)/(tau*tau)
+	}
+
+	A := a
+	var B float64
+	if delta*delta > phi*phi+v {
+		B = math.Log(delta*delta - phi*phi - v)
+	} else {
+		k := 1.0
+		for f(a-k*tau) < 0 {
+			k++
+		}
+		B = a - k*tau
+	}
+
+	fA, fB := f(A), f(B)
+	for math.Abs(B-A) > convergence {
+		C := A + (A-B)*fA/(fB-fA)
+		fC := f(C)
+		if fC*fB <= 0 {
+			A, fA = B, fB
+		} else {
+			fA /= 2
+		}
+		B, fB = C, fC
+	}
+	return math.Exp(A / 2)
+}
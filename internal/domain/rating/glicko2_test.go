@@ -0,0 +1,46 @@
+package rating
+
+import (
+	"math"
+	"testing"
+)
+
+// TestGlickmanExample 使用 Glickman "Example of the Glicko-2 system" 的計算例子（tau = 0.5）
+func TestGlickmanExample(t *testing.T) {
+	player := Glicko{Rating: 1500, Deviation: 200, Volatility: 0.06}
+	results := []Result{
+		{Opponent: Glicko{Rating: 1400, Deviation: 30}, Score: 1},
+		{Opponent: Glicko{Rating: 1550, Deviation: 100}, Score: 0},
+		{Opponent: Glicko{Rating: 1700, Deviation: 300}, Score: 0},
+	}
+
+	got := player.Update(results)
+	checks := []struct {
+		name      string
+		got, want float64
+		tolerance float64
+	}{
+		{"rating", got.Rating, 1464.06, 0.01},
+		{"deviation", got.Deviation, 151.52, 0.01},
+		{"volatility", got.Volatility, 0.05999, 0.00001},
+	}
+	for _, c := range checks {
+		if math.Abs(c.got-c.want) > c.tolerance {
+			t.Errorf("%s = %.5f, want %.5f", c.name, c.got, c.want)
+		}
+	}
+	if got.Games != 3 {
+		t.Errorf("games = %d, want 3", got.Games)
+	}
+}
+
+func TestUpdateWithoutGamesOnlyWidensDeviation(t *testing.T) {
+	player := Glicko{Rating: 1500, Deviation: 200, Volatility: 0.06}
+	got := player.Update(nil)
+	if got.Rating != player.Rating || got.Volatility != player.Volatility {
+		t.Errorf("rating or volatility changed: %+v", got)
+	}
+	if want := math.Sqrt(200*200/(glickoScale*glickoScale)+0.06*0.06) * glickoScale; math.Abs(got.Deviation-want) > 1e-9 {
+		t.Errorf("deviation = %.5f, want %.5f", got.Deviation, want)
+	}
+}
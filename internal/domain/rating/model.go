@@ -0,0 +1,71 @@
+package rating
+
+import (
+	"time"
+
+	"github.com/nelawu/BagchalGolang/internal/domain/game"
+)
+
+// PlayerRating 表示一位玩家的積分，虎羊兩方不對稱，因此另外記錄分陣營積分
+type PlayerRating struct {
+	PlayerID  string         `json:"playerId"`
+	Overall   Glicko         `json:"overall"`
+	Tiger     Glicko         `json:"tiger"` // 執虎時的積分
+	Goat      Glicko         `json:"goat"`  // 執羊時的積分
+	History   []HistoryEntry `json:"history"`
+	UpdatedAt time.Time      `json:"updatedAt"`
+}
+
+// HistoryEntry 表示一次積分變化
+type HistoryEntry struct {
+	GameID        string         `json:"gameId"`
+	OpponentID    string         `json:"opponentId"`
+	Side          game.PieceType `json:"side"`
+	Score         float64        `json:"score"`
+	OverallBefore float64        `json:"overallBefore"`
+	OverallAfter  float64        `json:"overallAfter"`
+	SideBefore    float64        `json:"sideBefore"`
+	SideAfter     float64        `json:"sideAfter"`
+	PlayedAt      time.Time      `json:"playedAt"`
+}
+
+// NewPlayerRating 創建初始積分
+func NewPlayerRating(playerID string) *PlayerRating {
+	return &PlayerRating{
+		PlayerID:  playerID,
+		Overall:   NewGlicko(),
+		Tiger:     NewGlicko(),
+		Goat:      NewGlicko(),
+		History:   []HistoryEntry{},
+		UpdatedAt: time.Now(),
+	}
+}
+
+// Clone 返回積分的副本，存儲中的積分可能正被其他請求讀取，更新時需先複製
+func (p *PlayerRating) Clone() *PlayerRating {
+	clone := *p
+	clone.History = append([]HistoryEntry{}, p.History...)
+	return &clone
+}
+
+// Side 返回指定陣營的積分，Empty 返回總積分
+func (p *PlayerRating) Side(side game.PieceType) Glicko {
+	switch side {
+	case game.Tiger:
+		return p.Tiger
+	case game.Goat:
+		return p.Goat
+	default:
+		return p.Overall
+	}
+}
+
+// setSide 設置指定陣營的積分
+func (p *PlayerRating) setSide(side game.PieceType, glicko Glicko) {
+	switch side {
+	case game.Tiger:
+		p.Tiger = glicko
+	case game.Goat:
+		p.Goat = glicko
+	}
+}
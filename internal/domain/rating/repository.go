@@ -0,0 +1,17 @@
+package rating
+
+import "errors"
+
+var ErrRatingNotFound = errors.New("rating not found")
+
+// Repository 定義積分資料存儲介面
+type Repository interface {
+	// Save 保存玩家積分
+	Save(rating *PlayerRating) error
+
+	// GetByPlayerID 根據玩家ID獲取積分
+	GetByPlayerID(playerID string) (*PlayerRating, error)
+
+	// List 列出所有玩家積分
+	List() ([]*PlayerRating, error)
+}
@@ -0,0 +1,144 @@
+package rating
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	"github.com/nelawu/BagchalGolang/internal/domain/game"
+)
+
+type Service struct {
+	repository Repository
+	players    game.PlayerDirectory
+	mu         sync.Mutex
+}
+
+func NewService(repository Repository, players game.PlayerDirectory) *Service {
+	return &Service{
+		repository: repository,
+		players:    players,
+	}
+}
+
+// GetPlayerRating 獲取玩家積分，尚未計分的玩家返回初始積分，未註冊的玩家返回 game.ErrPlayerNotFound
+// AI虛擬玩家不需要註冊
+func (s *Service) GetPlayerRating(playerID string) (*PlayerRating, error) {
+	if _, isAI := game.ParseAIPlayerID(playerID); !isAI {
+		exists, err := s.players.Exists(playerID)
+		if err != nil {
+			return nil, err
+		}
+		if !exists {
+			return nil, game.ErrPlayerNotFound
+		}
+	}
+	return s.lookup(playerID)
+}
+
+// lookup 從存儲獲取玩家積分，尚未計分的玩家返回初始積分
+func (s *Service) lookup(playerID string) (*PlayerRating, error) {
+	rating, err := s.repository.GetByPlayerID(playerID)
+	if err == ErrRatingNotFound {
+		return NewPlayerRating(playerID), nil
+	}
+	return rating, err
+}
+
+// Rating 返回玩家在指定陣營的積分數值，Empty 返回總積分
+func (s *Service) Rating(playerID string, side game.PieceType) float64 {
+	rating, err := s.lookup(playerID)
+	if err != nil {
+		return DefaultRating
+	}
+	return rating.Side(side).Rating
+}
+
+// HandleGameOver 在計分遊戲結束時更新雙方積分
+func (s *Service) HandleGameOver(g *game.Game) {
+	if !g.Rated || !g.State.IsGameOver {
+		return
+	}
+
+	tigerID := g.SidePlayerID(game.Tiger)
+	goatID := g.SidePlayerID(game.Goat)
+	if tigerID == "" || goatID == "" || tigerID == goatID {
+		return
+	}
+
+	tigerScore := 0.5
+	switch g.State.Winner {
+	case game.Tiger:
+		tigerScore = 1
+	case game.Goat:
+		tigerScore = 0
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tiger, err := s.lookup(tigerID)
+	if err != nil {
+		log.Printf("獲取積分失敗: %v", err)
+		return
+	}
+	goat, err := s.lookup(goatID)
+	if err != nil {
+		log.Printf("獲取積分失敗: %v", err)
+		return
+	}
+	// 存儲中的積分可能正被讀取，在副本上更新後再保存
+	tiger, goat = tiger.Clone(), goat.Clone()
+
+	now := time.Now()
+	tigerEntry := s.apply(tiger, goat, game.Tiger, tigerScore, g.ID, now)
+	goatEntry := s.apply(goat, tiger, game.Goat, 1-tigerScore, g.ID, now)
+
+	// 雙方都計算完成後再寫回，確保使用的是賽前積分
+	tiger.Overall, tiger.Tiger = tigerEntry.overall, tigerEntry.side
+	goat.Overall, goat.Goat = goatEntry.overall, goatEntry.side
+	tiger.History = append(tiger.History, tigerEntry.HistoryEntry)
+	goat.History = append(goat.History, goatEntry.HistoryEntry)
+	tiger.UpdatedAt, goat.UpdatedAt = now, now
+
+	if err := s.repository.Save(tiger); err != nil {
+		log.Printf("保存積分失敗: %v", err)
+	}
+	if err := s.repository.Save(goat); err != nil {
+		log.Printf("保存積分失敗: %v", err)
+	}
+}
+
+// pendingUpdate 表示一方尚未寫回的積分變化
+type pendingUpdate struct {
+	HistoryEntry
+	overall Glicko
+	side    Glicko
+}
+
+// apply 計算玩家在一局中的積分變化
+func (s *Service) apply(player, opponent *PlayerRating, side game.PieceType, score float64, gameID string, now time.Time) pendingUpdate {
+	opponentSide := game.Goat
+	if side == game.Goat {
+		opponentSide = game.Tiger
+	}
+
+	overall := player.Overall.Update([]Result{{Opponent: opponent.Overall, Score: score}})
+	sideRating := player.Side(side).Update([]Result{{Opponent: opponent.Side(opponentSide), Score: score}})
+
+	return pendingUpdate{
+		HistoryEntry: HistoryEntry{
+			GameID:        gameID,
+			OpponentID:    opponent.PlayerID,
+			Side:          side,
+			Score:         score,
+			OverallBefore: player.Overall.Rating,
+			OverallAfter:  overall.Rating,
+			SideBefore:    player.Side(side).Rating,
+			SideAfter:     sideRating.Rating,
+			PlayedAt:      now,
+		},
+		overall: overall,
+		side:    sideRating,
+	}
+}
@@ -0,0 +1,60 @@
+package rematch
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+)
+
+// OfferStatus 表示再戰邀請的狀態
+type OfferStatus string
+
+const (
+	OfferPending  OfferStatus = "pending"
+	OfferAccepted OfferStatus = "accepted"
+)
+
+// Offer 表示一局遊戲結束後的再戰邀請
+type Offer struct {
+	GameID     string      `json:"gameId"`
+	OfferedBy  string      `json:"offeredBy"`
+	OfferedTo  string      `json:"offeredTo"`
+	Status     OfferStatus `json:"status"`
+	NewGameID  string      `json:"newGameId,omitempty"` // 接受後創建的遊戲ID
+	SeriesID   string      `json:"seriesId,omitempty"`
+	CreatedAt  time.Time   `json:"createdAt"`
+	AcceptedAt *time.Time  `json:"acceptedAt,omitempty"`
+
+	// accepting 表示已被接受、正在鎖外創建再戰遊戲，此時狀態仍為 pending
+	accepting bool
+}
+
+// Series 表示同一對玩家之間連續再戰的系列比分
+type Series struct {
+	ID        string             `json:"id"`
+	PlayerIDs [2]string          `json:"playerIds"`
+	Scores    map[string]float64 `json:"scores"` // 玩家ID -> 得分（勝 1、和 0.5）
+	Draws     int                `json:"draws"`
+	GameIDs   []string           `json:"gameIds"`
+	UpdatedAt time.Time          `json:"updatedAt"`
+}
+
+// NewSeries 創建兩位玩家之間的系列
+func NewSeries(playerA, playerB string) *Series {
+	return &Series{
+		ID:        generateSeriesID(),
+		PlayerIDs: [2]string{playerA, playerB},
+		Scores:    map[string]float64{playerA: 0, playerB: 0},
+		GameIDs:   []string{},
+		UpdatedAt: time.Now(),
+	}
+}
+
+// generateSeriesID 生成系列ID
+func generateSeriesID() string {
+	suffix := make([]byte, 6)
+	if _, err := rand.Read(suffix); err != nil {
+		return "series_" + time.Now().Format("20060102150405.000000000")
+	}
+	return "series_" + hex.EncodeToString(suffix)
+}
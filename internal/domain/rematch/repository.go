@@ -0,0 +1,14 @@
+package rematch
+
+import "errors"
+
+var ErrSeriesNotFound = errors.New("series not found")
+
+// SeriesRepository 定義再戰系列資料存儲介面
+type SeriesRepository interface {
+	// Save 保存系列
+	Save(series *Series) error
+
+	// GetByID 根據ID獲取系列
+	GetByID(id string) (*Series, error)
+}
@@ -0,0 +1,262 @@
+package rematch
+
+import (
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/nelawu/BagchalGolang/internal/domain/game"
+)
+
+var (
+	ErrOfferNotFound     = errors.New("rematch offer not found")
+	ErrAlreadyRematched  = errors.New("game already has a rematch")
+	ErrAlreadyOffered    = errors.New("rematch already offered")
+	ErrNotOfferRecipient = errors.New("only the invited player can accept")
+	ErrNoOpponent        = errors.New("game has no opponent to rematch")
+)
+
+// GameSource 提供遊戲查詢與再戰創建，由 game.GameService 實現
+type GameSource interface {
+	GetGame(id string) (*game.Game, error)
+	CreateRematch(gameID, seriesID string) (*game.Game, error)
+}
+
+type RematchService struct {
+	series SeriesRepository
+	games  GameSource
+
+	offersMu sync.Mutex
+	offers   map[string]*Offer // 原遊戲ID -> 邀請
+
+	seriesMu sync.Mutex
+}
+
+func NewRematchService(series SeriesRepository, games GameSource) *RematchService {
+	return &RematchService{
+		series: series,
+		games:  games,
+		offers: make(map[string]*Offer),
+	}
+}
+
+// Offer 邀請對手再戰。對手為AI時立即創建再戰；對手已先發出邀請時視為接受
+func (s *RematchService) Offer(gameID, playerID string) (*Offer, error) {
+	original, err := s.games.GetGame(gameID)
+	if err != nil {
+		return nil, game.ErrGameNotFound
+	}
+	if !original.State.IsGameOver {
+		return nil, game.ErrGameNotOver
+	}
+	side := original.SideOf(playerID)
+	if side == game.Empty {
+		return nil, game.ErrForbidden
+	}
+	opponentSide := game.Goat
+	if side == game.Goat {
+		opponentSide = game.Tiger
+	}
+	opponentID := original.SidePlayerID(opponentSide)
+	if opponentID == "" {
+		return nil, ErrNoOpponent
+	}
+
+	_, opponentIsAI := original.SideAILevel(opponentSide)
+	offer, accepted, err := s.placeOffer(gameID, playerID, opponentID, opponentIsAI)
+	if err != nil || !accepted {
+		return offer, err
+	}
+	return s.accept(gameID, original)
+}
+
+// placeOffer 記錄再戰邀請；對手為AI或對手已先發出邀請時標記為接受中並返回 true
+func (s *RematchService) placeOffer(gameID, playerID, opponentID string, opponentIsAI bool) (*Offer, bool, error) {
+	s.offersMu.Lock()
+	defer s.offersMu.Unlock()
+
+	if offer, exists := s.offers[gameID]; exists {
+		switch {
+		case offer.Status == OfferAccepted || offer.accepting:
+			return nil, false, ErrAlreadyRematched
+		case offer.OfferedBy == playerID:
+			return nil, false, ErrAlreadyOffered
+		}
+		// 雙方都提出再戰
+		offer.accepting = true
+		return nil, true, nil
+	}
+
+	offer := &Offer{
+		GameID:    gameID,
+		OfferedBy: playerID,
+		OfferedTo: opponentID,
+		Status:    OfferPending,
+		CreatedAt: time.Now(),
+	}
+	s.offers[gameID] = offer
+
+	// AI總是立即接受
+	if opponentIsAI {
+		offer.accepting = true
+		return nil, true, nil
+	}
+	return s.copyOffer(offer), false, nil
+}
+
+// Accept 受邀玩家接受再戰
+func (s *RematchService) Accept(gameID, playerID string) (*Offer, error) {
+	original, err := s.games.GetGame(gameID)
+	if err != nil {
+		return nil, game.ErrGameNotFound
+	}
+	if err := s.claimOffer(gameID, playerID); err != nil {
+		return nil, err
+	}
+	return s.accept(gameID, original)
+}
+
+// claimOffer 檢查受邀玩家能否接受邀請，並標記為接受中，防止並發的接受重複創建遊戲
+func (s *RematchService) claimOffer(gameID, playerID string) error {
+	s.offersMu.Lock()
+	defer s.offersMu.Unlock()
+
+	offer, exists := s.offers[gameID]
+	if !exists {
+		return ErrOfferNotFound
+	}
+	if offer.Status == OfferAccepted || offer.accepting {
+		return ErrAlreadyRematched
+	}
+	if offer.OfferedTo != playerID {
+		return ErrNotOfferRecipient
+	}
+	offer.accepting = true
+	return nil
+}
+
+// Decline 拒絕或撤回再戰邀請
+func (s *RematchService) Decline(gameID, playerID string) error {
+	s.offersMu.Lock()
+	defer s.offersMu.Unlock()
+
+	offer, exists := s.offers[gameID]
+	if !exists || offer.Status != OfferPending {
+		return ErrOfferNotFound
+	}
+	if offer.accepting {
+		return ErrAlreadyRematched
+	}
+	if offer.OfferedBy != playerID && offer.OfferedTo != playerID {
+		return game.ErrForbidden
+	}
+	delete(s.offers, gameID)
+	return nil
+}
+
+// GetOffer 獲取遊戲的再戰邀請
+func (s *RematchService) GetOffer(gameID string) (*Offer, error) {
+	s.offersMu.Lock()
+	defer s.offersMu.Unlock()
+
+	offer, exists := s.offers[gameID]
+	if !exists {
+		return nil, ErrOfferNotFound
+	}
+	return s.copyOffer(offer), nil
+}
+
+// GetSeries 獲取系列比分
+func (s *RematchService) GetSeries(id string) (*Series, error) {
+	return s.series.GetByID(id)
+}
+
+// HandleGameOver 在系列中的遊戲結束時更新比分
+func (s *RematchService) HandleGameOver(g *game.Game) {
+	if g.SeriesID == "" {
+		return
+	}
+
+	s.seriesMu.Lock()
+	defer s.seriesMu.Unlock()
+
+	series, err := s.series.GetByID(g.SeriesID)
+	if err != nil {
+		return
+	}
+	recordResult(series, g)
+	if err := s.series.Save(series); err != nil {
+		log.Printf("保存再戰系列 %s 失敗: %v", series.ID, err)
+	}
+}
+
+// accept 為已標記接受中的邀請創建再戰遊戲，首次再戰時以原遊戲結果建立系列
+// 創建遊戲時可能要等AI下出第一步，因此在 offersMu 之外進行，失敗時邀請恢復為可接受
+func (s *RematchService) accept(gameID string, original *game.Game) (*Offer, error) {
+	seriesID, err := s.ensureSeries(original)
+	var newGame *game.Game
+	if err == nil {
+		newGame, err = s.games.CreateRematch(original.ID, seriesID)
+	}
+
+	s.offersMu.Lock()
+	defer s.offersMu.Unlock()
+
+	offer := s.offers[gameID]
+	offer.accepting = false
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	offer.Status = OfferAccepted
+	offer.NewGameID = newGame.ID
+	offer.SeriesID = seriesID
+	offer.AcceptedAt = &now
+	return s.copyOffer(offer), nil
+}
+
+// ensureSeries 返回原遊戲所屬的系列ID，不存在時創建並計入原遊戲結果
+func (s *RematchService) ensureSeries(original *game.Game) (string, error) {
+	if original.SeriesID != "" {
+		return original.SeriesID, nil
+	}
+
+	s.seriesMu.Lock()
+	defer s.seriesMu.Unlock()
+
+	series := NewSeries(original.SidePlayerID(game.Tiger), original.SidePlayerID(game.Goat))
+	recordResult(series, original)
+	if err := s.series.Save(series); err != nil {
+		return "", err
+	}
+	return series.ID, nil
+}
+
+// copyOffer 返回邀請的副本，避免調用方在鎖外讀取可變狀態
+func (s *RematchService) copyOffer(offer *Offer) *Offer {
+	copied := *offer
+	return &copied
+}
+
+// recordResult 將已結束遊戲的結果計入系列
+func recordResult(series *Series, g *game.Game) {
+	for _, id := range series.GameIDs {
+		if id == g.ID {
+			return
+		}
+	}
+
+	series.GameIDs = append(series.GameIDs, g.ID)
+	series.UpdatedAt = time.Now()
+	switch g.State.Winner {
+	case game.Empty:
+		series.Draws++
+		for _, playerID := range series.PlayerIDs {
+			series.Scores[playerID] += 0.5
+		}
+	default:
+		series.Scores[g.SidePlayerID(g.State.Winner)]++
+	}
+}
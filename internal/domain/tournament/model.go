@@ -0,0 +1,152 @@
+package tournament
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+
+	"github.com/nelawu/BagchalGolang/internal/domain/game"
+)
+
+// PairingSystem 表示編排方式
+type PairingSystem string
+
+const (
+	RoundRobin PairingSystem = "round_robin"
+	Swiss      PairingSystem = "swiss"
+)
+
+// Status 表示賽事狀態
+type Status string
+
+const (
+	StatusPending  Status = "pending"
+	StatusRunning  Status = "running"
+	StatusFinished Status = "finished"
+	StatusFailed   Status = "failed" // 無法編排下一輪，賽事停止
+)
+
+// 對局結果得分
+const (
+	WinPoints  = 1.0
+	DrawPoints = 0.5
+	ByePoints  = 1.0 // 瑞士制輪空得分
+)
+
+// RuleSet 表示賽事規則
+type RuleSet struct {
+	TimeControl     *game.TimeControl `json:"timeControl,omitempty"`
+	Rated           bool              `json:"rated"`
+	GamesPerPairing int               `json:"gamesPerPairing"` // 1 或 2，2 表示雙方各執虎、羊一局
+}
+
+// Participant 表示一位參賽者，可以是註冊玩家或AI
+type Participant struct {
+	ID      string `json:"id"`                // 玩家ID，AI為虛擬玩家ID（如 ai:2）
+	AILevel int    `json:"aiLevel,omitempty"` // AI參賽者的難度
+}
+
+// IsAI 是否為AI參賽者
+func (p Participant) IsAI() bool {
+	return p.AILevel > 0
+}
+
+// TournamentGame 表示一場配對中的一局
+type TournamentGame struct {
+	GameID   string         `json:"gameId"`
+	TigerID  string         `json:"tigerId"`
+	GoatID   string         `json:"goatId"`
+	Finished bool           `json:"finished"`
+	Winner   game.PieceType `json:"winner"` // Empty 且 Finished 表示和棋
+}
+
+// score 返回參賽者在此局的得分
+func (g *TournamentGame) score(participantID string) float64 {
+	if !g.Finished {
+		return 0
+	}
+	switch {
+	case g.Winner == game.Empty:
+		return DrawPoints
+	case g.Winner == game.Tiger && g.TigerID == participantID,
+		g.Winner == game.Goat && g.GoatID == participantID:
+		return WinPoints
+	}
+	return 0
+}
+
+// Pairing 表示一輪中兩位參賽者的對陣，PlayerB 為空表示輪空
+type Pairing struct {
+	PlayerA string           `json:"playerA"`
+	PlayerB string           `json:"playerB,omitempty"`
+	Games   []TournamentGame `json:"games"`
+}
+
+// IsBye 是否為輪空
+func (p *Pairing) IsBye() bool {
+	return p.PlayerB == ""
+}
+
+// finished 配對中的所有對局是否已結束
+func (p *Pairing) finished() bool {
+	for _, g := range p.Games {
+		if !g.Finished {
+			return false
+		}
+	}
+	return true
+}
+
+// Round 表示一輪比賽
+type Round struct {
+	Number   int       `json:"number"`
+	Pairings []Pairing `json:"pairings"`
+}
+
+// finished 本輪所有對局是否已結束
+func (r *Round) finished() bool {
+	for i := range r.Pairings {
+		if !r.Pairings[i].finished() {
+			return false
+		}
+	}
+	return true
+}
+
+// Tournament 表示一項賽事
+type Tournament struct {
+	ID           string        `json:"id"`
+	Name         string        `json:"name"`
+	System       PairingSystem `json:"system"`
+	RuleSet      RuleSet       `json:"ruleSet"`
+	Participants []Participant `json:"participants"`
+	TotalRounds  int           `json:"totalRounds"`
+	Rounds       []Round       `json:"rounds"`
+	Status       Status        `json:"status"`
+	Failure      string        `json:"failure,omitempty"` // 賽事失敗的原因
+	CreatedBy    string        `json:"createdBy"`
+	CreatedAt    time.Time     `json:"createdAt"`
+	UpdatedAt    time.Time     `json:"updatedAt"`
+}
+
+// Standing 表示積分榜中的一行
+type Standing struct {
+	Rank            int     `json:"rank"`
+	ParticipantID   string  `json:"participantId"`
+	Points          float64 `json:"points"`
+	Wins            int     `json:"wins"`
+	Draws           int     `json:"draws"`
+	Losses          int     `json:"losses"`
+	Byes            int     `json:"byes"`
+	Buchholz        float64 `json:"buchholz"`        // 對手總分
+	SonnebornBerger float64 `json:"sonnebornBerger"` // 各局所得分乘以對手總分之和
+}
+
+// generateTournamentID 生成賽事ID
+func generateTournamentID() string {
+	suffix := make([]byte, 6)
+	if _, err := rand.Read(suffix); err != nil {
+		return "tournament_" + time.Now().Format("20060102150405.000000000")
+	}
+	return "tournament_" + hex.EncodeToString(suffix)
+}
@@ -0,0 +1,168 @@
+package tournament
+
+import (
+	"math"
+)
+
+// defaultRounds 返回編排方式對應的預設輪數
+func defaultRounds(system PairingSystem, participants int) int {
+	switch system {
+	case RoundRobin:
+		// 單數人數時每輪有一人輪空，需要多一輪
+		if participants%2 == 1 {
+			return participants
+		}
+		return participants - 1
+	default:
+		return int(math.Max(1, math.Ceil(math.Log2(float64(participants)))))
+	}
+}
+
+// roundRobinPairs 使用循環法返回第 round 輪（從 1 開始）的對陣，空字串表示輪空
+func roundRobinPairs(ids []string, round int) [][2]string {
+	players := append([]string(nil), ids...)
+	if len(players)%2 == 1 {
+		players = append(players, "")
+	}
+	n := len(players)
+
+	// 固定第一位，其餘按輪次旋轉
+	rotated := make([]string, n)
+	rotated[0] = players[0]
+	for i := 1; i < n; i++ {
+		rotated[i] = players[1+(i-1+round-1)%(n-1)]
+	}
+
+	pairs := make([][2]string, 0, n/2)
+	for i := 0; i < n/2; i++ {
+		a, b := rotated[i], rotated[n-1-i]
+		// 輪流交換先後位置，避免第一位總是排在前面
+		if i == 0 && round%2 == 0 {
+			a, b = b, a
+		}
+		if a == "" {
+			a, b = b, a
+		}
+		pairs = append(pairs, [2]string{a, b})
+	}
+	return pairs
+}
+
+// swissPairs 按積分榜順序編排瑞士制對陣，盡量避免重複對陣，空字串表示輪空
+func swissPairs(standings []Standing, played map[string]map[string]bool, hadBye map[string]bool) [][2]string {
+	ids := make([]string, len(standings))
+	for i, standing := range standings {
+		ids[i] = standing.ParticipantID
+	}
+
+	var pairs [][2]string
+
+	// 單數人數時，排名最低且未輪空過的參賽者輪空
+	if len(ids)%2 == 1 {
+		byeIndex := len(ids) - 1
+		for i := len(ids) - 1; i >= 0; i-- {
+			if !hadBye[ids[i]] {
+				byeIndex = i
+				break
+			}
+		}
+		pairs = append(pairs, [2]string{ids[byeIndex], ""})
+		ids = append(ids[:byeIndex:byeIndex], ids[byeIndex+1:]...)
+	}
+
+	// 先嘗試完全避免重複對陣，無解時允許重複
+	if result, ok := pairRemaining(ids, played, false); ok {
+		return append(pairs, result...)
+	}
+	result, _ := pairRemaining(ids, played, true)
+	return append(pairs, result...)
+}
+
+// pairRemaining 回溯搜索：排名最高者與最接近的可行對手配對
+func pairRemaining(ids []string, played map[string]map[string]bool, allowRematch bool) ([][2]string, bool) {
+	if len(ids) == 0 {
+		return nil, true
+	}
+
+	first := ids[0]
+	for i := 1; i < len(ids); i++ {
+		opponent := ids[i]
+		if !allowRematch && played[first][opponent] {
+			continue
+		}
+
+		rest := make([]string, 0, len(ids)-2)
+		rest = append(rest, ids[1:i]...)
+		rest = append(rest, ids[i+1:]...)
+		if result, ok := pairRemaining(rest, played, allowRematch); ok {
+			return append([][2]string{{first, opponent}}, result...), true
+		}
+	}
+	return nil, false
+}
+
+// assignGames 為一組對陣分配陣營，雙局制雙方各執虎、羊一局，單局制讓執虎較少的一方執虎
+func assignGames(a, b string, gamesPerPairing int, tigerCount map[string]int) []TournamentGame {
+	if gamesPerPairing == 2 {
+		return []TournamentGame{
+			{TigerID: a, GoatID: b},
+			{TigerID: b, GoatID: a},
+		}
+	}
+	if tigerCount[b] < tigerCount[a] {
+		a, b = b, a
+	}
+	return []TournamentGame{{TigerID: a, GoatID: b}}
+}
+
+// history 統計已有的對陣、輪空及執虎次數
+func history(t *Tournament) (played map[string]map[string]bool, hadBye map[string]bool, tigerCount map[string]int) {
+	played = make(map[string]map[string]bool)
+	hadBye = make(map[string]bool)
+	tigerCount = make(map[string]int)
+	for _, p := range t.Participants {
+		played[p.ID] = make(map[string]bool)
+	}
+	for _, round := range t.Rounds {
+		for _, pairing := range round.Pairings {
+			if pairing.IsBye() {
+				hadBye[pairing.PlayerA] = true
+				continue
+			}
+			played[pairing.PlayerA][pairing.PlayerB] = true
+			played[pairing.PlayerB][pairing.PlayerA] = true
+			for _, g := range pairing.Games {
+				tigerCount[g.TigerID]++
+			}
+		}
+	}
+	return played, hadBye, tigerCount
+}
+
+// nextPairings 計算下一輪的對陣及對局分配
+func nextPairings(t *Tournament) []Pairing {
+	round := len(t.Rounds) + 1
+	played, hadBye, tigerCount := history(t)
+
+	var pairs [][2]string
+	switch t.System {
+	case RoundRobin:
+		ids := make([]string, len(t.Participants))
+		for i, p := range t.Participants {
+			ids[i] = p.ID
+		}
+		pairs = roundRobinPairs(ids, round)
+	default:
+		pairs = swissPairs(Standings(t), played, hadBye)
+	}
+
+	pairings := make([]Pairing, 0, len(pairs))
+	for _, pair := range pairs {
+		pairing := Pairing{PlayerA: pair[0], PlayerB: pair[1]}
+		if !pairing.IsBye() {
+			pairing.Games = assignGames(pair[0], pair[1], t.RuleSet.GamesPerPairing, tigerCount)
+		}
+		pairings = append(pairings, pairing)
+	}
+	return pairings
+}
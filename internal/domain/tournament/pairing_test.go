@@ -0,0 +1,228 @@
+package tournament
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/nelawu/BagchalGolang/internal/domain/game"
+)
+
+func participantIDs(n int) []string {
+	ids := make([]string, n)
+	for i := range ids {
+		ids[i] = fmt.Sprintf("p%d", i+1)
+	}
+	return ids
+}
+
+func TestDefaultRounds(t *testing.T) {
+	tests := []struct {
+		system       PairingSystem
+		participants int
+		want         int
+	}{
+		{RoundRobin, 2, 1},
+		{RoundRobin, 3, 3},
+		{RoundRobin, 4, 3},
+		{RoundRobin, 7, 7},
+		{Swiss, 2, 1},
+		{Swiss, 5, 3},
+		{Swiss, 8, 3},
+		{Swiss, 9, 4},
+	}
+	for _, tt := range tests {
+		if got := defaultRounds(tt.system, tt.participants); got != tt.want {
+			t.Errorf("defaultRounds(%s, %d) = %d, want %d", tt.system, tt.participants, got, tt.want)
+		}
+	}
+}
+
+// TestRoundRobinPairs 檢查每位參賽者每輪恰好出現一次，任意兩人恰好相遇一次，單數人數時每人輪空一次
+func TestRoundRobinPairs(t *testing.T) {
+	for n := 2; n <= 9; n++ {
+		ids := participantIDs(n)
+		met := make(map[[2]string]int)
+		byes := make(map[string]int)
+		for round := 1; round <= defaultRounds(RoundRobin, n); round++ {
+			seen := make(map[string]bool)
+			for _, pair := range roundRobinPairs(ids, round) {
+				a, b := pair[0], pair[1]
+				if a == "" {
+					t.Fatalf("n=%d round %d: bye placed first in %v", n, round, pair)
+				}
+				for _, id := range pair {
+					if id != "" && seen[id] {
+						t.Fatalf("n=%d round %d: %s paired twice", n, round, id)
+					}
+					seen[id] = true
+				}
+				if b == "" {
+					byes[a]++
+					continue
+				}
+				if a > b {
+					a, b = b, a
+				}
+				met[[2]string{a, b}]++
+			}
+			for _, id := range ids {
+				if !seen[id] {
+					t.Fatalf("n=%d round %d: %s missing", n, round, id)
+				}
+			}
+		}
+
+		for i, a := range ids {
+			for _, b := range ids[i+1:] {
+				key := [2]string{a, b}
+				if a > b {
+					key = [2]string{b, a}
+				}
+				if met[key] != 1 {
+					t.Errorf("n=%d: %s and %s met %d times", n, a, b, met[key])
+				}
+			}
+			wantByes := n % 2
+			if byes[a] != wantByes {
+				t.Errorf("n=%d: %s had %d byes, want %d", n, a, byes[a], wantByes)
+			}
+		}
+	}
+}
+
+func standingsFor(ids ...string) []Standing {
+	standings := make([]Standing, len(ids))
+	for i, id := range ids {
+		standings[i] = Standing{Rank: i + 1, ParticipantID: id}
+	}
+	return standings
+}
+
+func playedPairs(pairs ...[2]string) map[string]map[string]bool {
+	played := make(map[string]map[string]bool)
+	for _, pair := range pairs {
+		for _, id := range pair {
+			if played[id] == nil {
+				played[id] = make(map[string]bool)
+			}
+		}
+		played[pair[0]][pair[1]] = true
+		played[pair[1]][pair[0]] = true
+	}
+	return played
+}
+
+func TestSwissPairs(t *testing.T) {
+	tests := []struct {
+		name      string
+		standings []Standing
+		played    map[string]map[string]bool
+		hadBye    map[string]bool
+		want      [][2]string
+	}{
+		{
+			name:      "top down without history",
+			standings: standingsFor("a", "b", "c", "d"),
+			want:      [][2]string{{"a", "b"}, {"c", "d"}},
+		},
+		{
+			name:      "skips a repeat",
+			standings: standingsFor("a", "b", "c", "d"),
+			played:    playedPairs([2]string{"a", "b"}),
+			want:      [][2]string{{"a", "c"}, {"b", "d"}},
+		},
+		{
+			// a-b 可行，但剩下的 c-d 已對陣過，需要回溯改為 a-c
+			name:      "backtracks when the rest cannot be paired",
+			standings: standingsFor("a", "b", "c", "d"),
+			played:    playedPairs([2]string{"c", "d"}),
+			want:      [][2]string{{"a", "c"}, {"b", "d"}},
+		},
+		{
+			// c 已與 d、e、f 對陣過，a-b 之後 c 無對手可配，只能回溯讓 a 對 c
+			name:      "deep backtrack with six players",
+			standings: standingsFor("a", "b", "c", "d", "e", "f"),
+			played:    playedPairs([2]string{"c", "d"}, [2]string{"c", "e"}, [2]string{"c", "f"}),
+			want:      [][2]string{{"a", "c"}, {"b", "d"}, {"e", "f"}},
+		},
+		{
+			name:      "allows a rematch when unavoidable",
+			standings: standingsFor("a", "b"),
+			played:    playedPairs([2]string{"a", "b"}),
+			want:      [][2]string{{"a", "b"}},
+		},
+		{
+			name:      "odd count gives the lowest ranked a bye",
+			standings: standingsFor("a", "b", "c"),
+			want:      [][2]string{{"c", ""}, {"a", "b"}},
+		},
+		{
+			name:      "bye skips players who already had one",
+			standings: standingsFor("a", "b", "c", "d", "e"),
+			hadBye:    map[string]bool{"e": true, "d": true},
+			want:      [][2]string{{"c", ""}, {"a", "b"}, {"d", "e"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			played := tt.played
+			if played == nil {
+				played = map[string]map[string]bool{}
+			}
+			got := swissPairs(tt.standings, played, tt.hadBye)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAssignGames(t *testing.T) {
+	double := assignGames("a", "b", 2, nil)
+	if want := []TournamentGame{{TigerID: "a", GoatID: "b"}, {TigerID: "b", GoatID: "a"}}; !reflect.DeepEqual(double, want) {
+		t.Errorf("double = %v, want %v", double, want)
+	}
+	single := assignGames("a", "b", 1, map[string]int{"a": 2, "b": 1})
+	if want := []TournamentGame{{TigerID: "b", GoatID: "a"}}; !reflect.DeepEqual(single, want) {
+		t.Errorf("single = %v, want %v", single, want)
+	}
+}
+
+// TestNextPairingsSwissAvoidsRepeats 以完整賽事模擬瑞士制，確認人數足夠時不重複對陣
+func TestNextPairingsSwissAvoidsRepeats(t *testing.T) {
+	tournament := &Tournament{System: Swiss, RuleSet: RuleSet{GamesPerPairing: 1}, TotalRounds: 3}
+	for _, id := range participantIDs(7) {
+		tournament.Participants = append(tournament.Participants, Participant{ID: id})
+	}
+
+	met := make(map[[2]string]bool)
+	byes := make(map[string]bool)
+	for round := 1; round <= tournament.TotalRounds; round++ {
+		pairings := nextPairings(tournament)
+		for i := range pairings {
+			pairing := &pairings[i]
+			if pairing.IsBye() {
+				if byes[pairing.PlayerA] {
+					t.Errorf("round %d: %s got a second bye", round, pairing.PlayerA)
+				}
+				byes[pairing.PlayerA] = true
+				continue
+			}
+			key := [2]string{pairing.PlayerA, pairing.PlayerB}
+			if key[0] > key[1] {
+				key[0], key[1] = key[1], key[0]
+			}
+			if met[key] {
+				t.Errorf("round %d: %s and %s paired again", round, key[0], key[1])
+			}
+			met[key] = true
+			// 執虎方獲勝，使積分榜每輪變化
+			for j := range pairing.Games {
+				pairing.Games[j].Finished = true
+				pairing.Games[j].Winner = game.Tiger
+			}
+		}
+		tournament.Rounds = append(tournament.Rounds, Round{Number: round, Pairings: pairings})
+	}
+}
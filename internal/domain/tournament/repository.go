@@ -0,0 +1,13 @@
+package tournament
+
+// TournamentRepository 定義賽事資料存儲介面
+type TournamentRepository interface {
+	// Save 保存賽事
+	Save(tournament *Tournament) error
+
+	// GetByID 根據ID獲取賽事
+	GetByID(id string) (*Tournament, error)
+
+	// GetByGameID 根據賽事中的遊戲ID獲取賽事
+	GetByGameID(gameID string) (*Tournament, error)
+}
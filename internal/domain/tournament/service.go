@@ -0,0 +1,315 @@
+package tournament
+
+import (
+	"errors"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nelawu/BagchalGolang/internal/domain/game"
+)
+
+var (
+	ErrTournamentNotFound   = errors.New("tournament not found")
+	ErrInvalidTournament    = errors.New("invalid tournament settings")
+	ErrNotOrganizer         = errors.New("only the organizer can manage the tournament")
+	ErrAlreadyStarted       = errors.New("tournament already started")
+	ErrDuplicateParticipant = errors.New("duplicate participant")
+)
+
+const (
+	minParticipants = 2
+	maxParticipants = 64
+	maxAILevel      = 3
+
+	// aiQueueSize 等待AI開始下棋的對局數，隊列已滿時另開 goroutine 等待空位
+	aiQueueSize = 128
+
+	// aiGameSlots 同時進行的AI之間對局數，有人類參與的對局不佔用名額
+	aiGameSlots = 2
+)
+
+// aiJob 需要AI下棋的新對局
+type aiJob struct {
+	gameID string
+	aiOnly bool // 雙方均為AI，會在一次 AdvanceAI 中下完整局
+}
+
+// GameCreator 負責創建賽事對局並在輪到AI時讓AI下棋，由 game.GameService 實現
+type GameCreator interface {
+	CreateMatchedGame(tigerPlayerID, goatPlayerID string, timeControl *game.TimeControl, rated bool) (*game.Game, error)
+	AdvanceAI(gameID string) (*game.Game, error)
+}
+
+type TournamentService struct {
+	repository TournamentRepository
+	games      GameCreator
+	players    game.PlayerDirectory
+	mu         sync.Mutex
+
+	jobs  chan aiJob
+	slots chan struct{} // AI之間對局的並行名額
+	stop  chan struct{}
+	once  sync.Once
+}
+
+func NewTournamentService(repository TournamentRepository, games GameCreator, players game.PlayerDirectory) *TournamentService {
+	return &TournamentService{
+		repository: repository,
+		games:      games,
+		players:    players,
+		jobs:       make(chan aiJob, aiQueueSize),
+		slots:      make(chan struct{}, aiGameSlots),
+		stop:       make(chan struct{}),
+	}
+}
+
+// Start 啟動背景循環，為每局新對局啟動 goroutine 讓AI下棋，避免在HTTP請求內遞歸編排後續輪次
+// 有人類參與的對局只需AI下一步，立即進行；AI之間的對局會下完整局，受 aiGameSlots 限制
+func (s *TournamentService) Start() {
+	go func() {
+		for {
+			select {
+			case job := <-s.jobs:
+				go s.play(job)
+			case <-s.stop:
+				return
+			}
+		}
+	}()
+}
+
+// play 讓對局中的AI下棋，AI之間的對局需先取得名額
+func (s *TournamentService) play(job aiJob) {
+	if job.aiOnly {
+		select {
+		case s.slots <- struct{}{}:
+			defer func() { <-s.slots }()
+		case <-s.stop:
+			return
+		}
+	}
+	if _, err := s.games.AdvanceAI(job.gameID); err != nil {
+		log.Printf("賽事對局 %s AI下棋失敗: %v", job.gameID, err)
+	}
+}
+
+// Stop 停止背景循環
+func (s *TournamentService) Stop() {
+	s.once.Do(func() { close(s.stop) })
+}
+
+// CreateTournament 創建賽事，rounds 為 0 時使用編排方式的預設輪數
+func (s *TournamentService) CreateTournament(organizerID, name string, system PairingSystem, participants []Participant, ruleSet RuleSet, rounds int) (*Tournament, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || len(participants) < minParticipants || len(participants) > maxParticipants {
+		return nil, ErrInvalidTournament
+	}
+	if system != RoundRobin && system != Swiss {
+		return nil, ErrInvalidTournament
+	}
+	if ruleSet.GamesPerPairing == 0 {
+		ruleSet.GamesPerPairing = 2
+	}
+	if ruleSet.GamesPerPairing != 1 && ruleSet.GamesPerPairing != 2 {
+		return nil, ErrInvalidTournament
+	}
+
+	seen := make(map[string]bool, len(participants))
+	for i := range participants {
+		participant := &participants[i]
+		if participant.IsAI() {
+			if participant.AILevel > maxAILevel {
+				return nil, ErrInvalidTournament
+			}
+			participant.ID = game.AIPlayerID(participant.AILevel)
+		} else {
+			exists, err := s.players.Exists(participant.ID)
+			if err != nil {
+				return nil, err
+			}
+			if !exists {
+				return nil, game.ErrPlayerNotFound
+			}
+		}
+		if seen[participant.ID] {
+			return nil, ErrDuplicateParticipant
+		}
+		seen[participant.ID] = true
+	}
+
+	switch {
+	case rounds == 0:
+		rounds = defaultRounds(system, len(participants))
+	case rounds < 0, system == RoundRobin && rounds != defaultRounds(system, len(participants)):
+		// 循環賽輪數由人數決定
+		return nil, ErrInvalidTournament
+	}
+
+	tournament := &Tournament{
+		ID:           generateTournamentID(),
+		Name:         name,
+		System:       system,
+		RuleSet:      ruleSet,
+		Participants: participants,
+		TotalRounds:  rounds,
+		Rounds:       []Round{},
+		Status:       StatusPending,
+		CreatedBy:    organizerID,
+		CreatedAt:    time.Now(),
+		UpdatedAt:    time.Now(),
+	}
+	if err := s.repository.Save(tournament); err != nil {
+		return nil, err
+	}
+	return tournament, nil
+}
+
+// GetTournament 根據ID獲取賽事
+func (s *TournamentService) GetTournament(id string) (*Tournament, error) {
+	return s.repository.GetByID(id)
+}
+
+// GetStandings 獲取賽事積分榜
+func (s *TournamentService) GetStandings(id string) ([]Standing, error) {
+	tournament, err := s.repository.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	return Standings(tournament), nil
+}
+
+// StartTournament 由主辦者開始賽事並編排第一輪
+func (s *TournamentService) StartTournament(id, playerID string) (*Tournament, error) {
+	s.mu.Lock()
+	tournament, err := s.repository.GetByID(id)
+	if err != nil {
+		s.mu.Unlock()
+		return nil, err
+	}
+	if tournament.CreatedBy != playerID {
+		s.mu.Unlock()
+		return nil, ErrNotOrganizer
+	}
+	if tournament.Status != StatusPending {
+		s.mu.Unlock()
+		return nil, ErrAlreadyStarted
+	}
+
+	// 第一輪創建成功後才標記為進行中，失敗時賽事保持待開始，可重試
+	jobs, err := s.startRoundLocked(tournament)
+	if err == nil {
+		tournament.Status = StatusRunning
+		err = s.repository.Save(tournament)
+	}
+	s.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	s.advanceAI(jobs)
+	return s.repository.GetByID(id)
+}
+
+// HandleGameOver 記錄賽事對局結果，本輪結束時自動編排下一輪
+func (s *TournamentService) HandleGameOver(g *game.Game) {
+	s.mu.Lock()
+	tournament, err := s.repository.GetByGameID(g.ID)
+	if err != nil {
+		s.mu.Unlock()
+		return
+	}
+
+	current := &tournament.Rounds[len(tournament.Rounds)-1]
+	if !recordResult(current, g) {
+		s.mu.Unlock()
+		return
+	}
+	tournament.UpdatedAt = time.Now()
+
+	var jobs []aiJob
+	if current.finished() {
+		if len(tournament.Rounds) >= tournament.TotalRounds {
+			tournament.Status = StatusFinished
+		} else {
+			jobs, err = s.startRoundLocked(tournament)
+			if err != nil {
+				log.Printf("編排賽事 %s 下一輪失敗: %v", tournament.ID, err)
+				tournament.Status = StatusFailed
+				tournament.Failure = "無法編排下一輪"
+			}
+		}
+	}
+	if err := s.repository.Save(tournament); err != nil {
+		log.Printf("保存賽事 %s 失敗: %v", tournament.ID, err)
+	}
+	s.mu.Unlock()
+
+	s.advanceAI(jobs)
+}
+
+// startRoundLocked 編排新一輪並創建對局，返回需要AI下棋的對局，調用前需持有鎖
+// 只在所有對局創建成功後加入新一輪，由調用者保存賽事
+func (s *TournamentService) startRoundLocked(tournament *Tournament) ([]aiJob, error) {
+	round := Round{
+		Number:   len(tournament.Rounds) + 1,
+		Pairings: nextPairings(tournament),
+	}
+
+	var jobs []aiJob
+	for i := range round.Pairings {
+		pairing := &round.Pairings[i]
+		for j := range pairing.Games {
+			tournamentGame := &pairing.Games[j]
+			newGame, err := s.games.CreateMatchedGame(tournamentGame.TigerID, tournamentGame.GoatID, tournament.RuleSet.TimeControl, tournament.RuleSet.Rated)
+			if err != nil {
+				return nil, err
+			}
+			tournamentGame.GameID = newGame.ID
+
+			_, tigerAI := game.ParseAIPlayerID(tournamentGame.TigerID)
+			_, goatAI := game.ParseAIPlayerID(tournamentGame.GoatID)
+			if tigerAI || goatAI {
+				jobs = append(jobs, aiJob{gameID: newGame.ID, aiOnly: tigerAI && goatAI})
+			}
+		}
+	}
+
+	tournament.Rounds = append(tournament.Rounds, round)
+	tournament.UpdatedAt = time.Now()
+	return jobs, nil
+}
+
+// advanceAI 將有AI參與的新對局交給背景循環，不阻塞
+func (s *TournamentService) advanceAI(jobs []aiJob) {
+	for _, job := range jobs {
+		select {
+		case s.jobs <- job:
+		default:
+			// 隊列已滿時不能丟棄對局，否則賽事會停在該局
+			go func(job aiJob) {
+				select {
+				case s.jobs <- job:
+				case <-s.stop:
+				}
+			}(job)
+		}
+	}
+}
+
+// recordResult 將遊戲結果寫入本輪對局，遊戲不屬於本輪或已記錄時返回 false
+func recordResult(round *Round, g *game.Game) bool {
+	for i := range round.Pairings {
+		for j := range round.Pairings[i].Games {
+			tournamentGame := &round.Pairings[i].Games[j]
+			if tournamentGame.GameID != g.ID || tournamentGame.Finished {
+				continue
+			}
+			tournamentGame.Finished = true
+			tournamentGame.Winner = g.State.Winner
+			return true
+		}
+	}
+	return false
+}
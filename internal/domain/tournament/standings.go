@@ -0,0 +1,90 @@
+package tournament
+
+import (
+	"sort"
+)
+
+// Standings 根據已完成的對局計算積分榜
+// 排名依次比較：總分、主要破同分（瑞士制為 Buchholz，循環賽為 Sonneborn-Berger）、次要破同分、勝局數
+func Standings(t *Tournament) []Standing {
+	byID := make(map[string]*Standing, len(t.Participants))
+	order := make([]*Standing, 0, len(t.Participants))
+	for _, p := range t.Participants {
+		standing := &Standing{ParticipantID: p.ID}
+		byID[p.ID] = standing
+		order = append(order, standing)
+	}
+
+	// 第一遍：計算總分與勝負
+	for _, round := range t.Rounds {
+		for _, pairing := range round.Pairings {
+			if pairing.IsBye() {
+				standing := byID[pairing.PlayerA]
+				standing.Byes++
+				if t.System == Swiss {
+					standing.Points += ByePoints
+				}
+				continue
+			}
+			for _, g := range pairing.Games {
+				if !g.Finished {
+					continue
+				}
+				for _, id := range []string{g.TigerID, g.GoatID} {
+					score := g.score(id)
+					standing := byID[id]
+					standing.Points += score
+					switch score {
+					case WinPoints:
+						standing.Wins++
+					case DrawPoints:
+						standing.Draws++
+					default:
+						standing.Losses++
+					}
+				}
+			}
+		}
+	}
+
+	// 第二遍：根據對手總分計算破同分
+	for _, round := range t.Rounds {
+		for _, pairing := range round.Pairings {
+			if pairing.IsBye() {
+				continue
+			}
+			a, b := byID[pairing.PlayerA], byID[pairing.PlayerB]
+			a.Buchholz += b.Points
+			b.Buchholz += a.Points
+			for _, g := range pairing.Games {
+				a.SonnebornBerger += g.score(a.ParticipantID) * b.Points
+				b.SonnebornBerger += g.score(b.ParticipantID) * a.Points
+			}
+		}
+	}
+
+	sort.SliceStable(order, func(i, j int) bool {
+		a, b := order[i], order[j]
+		if a.Points != b.Points {
+			return a.Points > b.Points
+		}
+		primaryA, primaryB, secondaryA, secondaryB := a.Buchholz, b.Buchholz, a.SonnebornBerger, b.SonnebornBerger
+		if t.System == RoundRobin {
+			primaryA, primaryB, secondaryA, secondaryB = secondaryA, secondaryB, primaryA, primaryB
+		}
+		if primaryA != primaryB {
+			return primaryA > primaryB
+		}
+		if secondaryA != secondaryB {
+			return secondaryA > secondaryB
+		}
+		return a.Wins > b.Wins
+	})
+
+	standings := make([]Standing, len(order))
+	for i, standing := range order {
+		standing.Rank = i + 1
+		standings[i] = *standing
+	}
+	return standings
+}
@@ -0,0 +1,111 @@
+package tournament
+
+import (
+	"testing"
+
+	"github.com/nelawu/BagchalGolang/internal/domain/game"
+)
+
+// result 返回一局已結束的單局對陣，score 為 a 的得分
+func result(a, b string, score float64) Pairing {
+	winner := game.Empty
+	switch score {
+	case WinPoints:
+		winner = game.Tiger
+	case 0:
+		winner = game.Goat
+	}
+	return Pairing{PlayerA: a, PlayerB: b, Games: []TournamentGame{{TigerID: a, GoatID: b, Finished: true, Winner: winner}}}
+}
+
+// tieBreakTournament 返回兩輪後 b 與 f 同分，且 Buchholz（b 較高）與 Sonneborn-Berger（f 較高）排序相反的賽事
+func tieBreakTournament(system PairingSystem) *Tournament {
+	t := &Tournament{System: system}
+	for _, id := range []string{"a", "b", "c", "d", "e", "f"} {
+		t.Participants = append(t.Participants, Participant{ID: id})
+	}
+	t.Rounds = []Round{
+		{Number: 1, Pairings: []Pairing{result("a", "f", 0.5), result("b", "e", 0), result("c", "d", 0.5)}},
+		{Number: 2, Pairings: []Pairing{result("a", "b", 0), result("c", "f", 0.5), result("d", "e", 0.5)}},
+	}
+	return t
+}
+
+func rankOrder(standings []Standing) []string {
+	order := make([]string, len(standings))
+	for i, standing := range standings {
+		if standing.Rank != i+1 {
+			return nil
+		}
+		order[i] = standing.ParticipantID
+	}
+	return order
+}
+
+func TestStandingsTieBreaks(t *testing.T) {
+	tests := []struct {
+		system PairingSystem
+		want   []string
+	}{
+		// 瑞士制先比 Buchholz：d(2.5) > c、b(2.0，c 的 SB 較高) > f(1.5)
+		{Swiss, []string{"e", "d", "c", "b", "f", "a"}},
+		// 循環賽先比 Sonneborn-Berger：d(1.25) > c(1.0) > f(0.75) > b(0.5)
+		{RoundRobin, []string{"e", "d", "c", "f", "b", "a"}},
+	}
+	for _, tt := range tests {
+		standings := Standings(tieBreakTournament(tt.system))
+		got := rankOrder(standings)
+		if len(got) != len(tt.want) {
+			t.Fatalf("%s: bad ranks %+v", tt.system, standings)
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("%s: order %v, want %v", tt.system, got, tt.want)
+				break
+			}
+		}
+	}
+}
+
+func TestStandingsScores(t *testing.T) {
+	standings := Standings(tieBreakTournament(Swiss))
+	byID := make(map[string]Standing)
+	for _, standing := range standings {
+		byID[standing.ParticipantID] = standing
+	}
+	b := byID["b"]
+	if b.Points != 1 || b.Wins != 1 || b.Losses != 1 || b.Draws != 0 || b.Buchholz != 2 || b.SonnebornBerger != 0.5 {
+		t.Errorf("b = %+v", b)
+	}
+	f := byID["f"]
+	if f.Points != 1 || f.Draws != 2 || f.Buchholz != 1.5 || f.SonnebornBerger != 0.75 {
+		t.Errorf("f = %+v", f)
+	}
+}
+
+func TestStandingsByes(t *testing.T) {
+	for _, tt := range []struct {
+		system PairingSystem
+		points float64
+	}{
+		{Swiss, ByePoints},
+		{RoundRobin, 0},
+	} {
+		tournament := &Tournament{
+			System:       tt.system,
+			Participants: []Participant{{ID: "a"}, {ID: "b"}, {ID: "c"}},
+			Rounds: []Round{{Number: 1, Pairings: []Pairing{
+				{PlayerA: "c"},
+				result("a", "b", 1),
+			}}},
+		}
+		for _, standing := range Standings(tournament) {
+			if standing.ParticipantID != "c" {
+				continue
+			}
+			if standing.Byes != 1 || standing.Points != tt.points || standing.Losses != 0 {
+				t.Errorf("%s: bye standing %+v, want %v points", tt.system, standing, tt.points)
+			}
+		}
+	}
+}